@@ -10,6 +10,6 @@ require (
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
-	golang.org/x/sys v0.25.0 // indirect
+	golang.org/x/sys v0.25.0
 	golang.org/x/text v0.18.0 // indirect
 )
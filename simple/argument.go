@@ -1,8 +1,20 @@
 package simple
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+
+	gcers "github.com/PlayerR9/errors"
+)
+
+const (
+	// Pipe is the separator between alternative usages of an argument.
+	Pipe string = " | "
+
+	// Hellipsis is the suffix of an argument that can be repeated.
+	Hellipsis string = "..."
 )
 
 var (
@@ -12,14 +24,26 @@ var (
 
 func init() {
 	NoArguments = &Argument{
-		args: nil,
+		positionals: nil,
+		min:         0,
+		max:         0,
 	}
 }
 
 // Argument is a struct that represents an argument.
+//
+// The i-th argument passed on the command line is parsed with the i-th positional
+// argument; arguments past the last positional argument are parsed with the last
+// one.
 type Argument struct {
-	// args is the list of arguments.
-	args []string
+	// positionals is the list of positional arguments.
+	positionals []*Positional
+
+	// min is the minimum number of arguments.
+	min int
+
+	// max is the maximum number of arguments. -1 means no maximum.
+	max int
 }
 
 // Fix implements the errors.Fixer interface.
@@ -28,6 +52,30 @@ func (a *Argument) Fix() error {
 		return nil
 	}
 
+	if a.min < 0 {
+		return errors.New("minimum number of arguments cannot be negative")
+	} else if a.max != -1 && a.max < a.min {
+		return fmt.Errorf("maximum number of arguments (%d) cannot be less than the minimum (%d)", a.max, a.min)
+	} else if a.max != 0 && len(a.positionals) == 0 {
+		return errors.New("at least one positional argument must be declared")
+	}
+
+	seen := make(map[string]struct{}, len(a.positionals))
+
+	for i, pos := range a.positionals {
+		err := gcers.Fix("positional argument "+strconv.Itoa(i+1), pos, false)
+		if err != nil {
+			return err
+		}
+
+		_, ok := seen[pos.Name]
+		if ok {
+			return fmt.Errorf("positional argument %q is declared more than once", pos.Name)
+		}
+
+		seen[pos.Name] = struct{}{}
+	}
+
 	return nil
 }
 
@@ -36,16 +84,129 @@ func (a *Argument) Fix() error {
 // Returns:
 //   - string: The string representation of the argument.
 func (a Argument) String() string {
-	elems := make([]string, len(a.args))
-	copy(elems, a.args)
+	// [] optional
+	// | mutually exclusive
+	// ... repeating
 
-	for i := 0; i < len(elems); i++ {
-		elems[i] = "<" + elems[i] + ">"
+	if a.max == 0 {
+		return ""
+	}
+
+	if a.max == -1 {
+		elems := make([]string, 0, a.min+1)
+
+		for i := 0; i < a.min; i++ {
+			elems = append(elems, write_arg(a.name_at(i)))
+		}
+
+		last := len(a.positionals) - 1
+
+		if a.min > last {
+			elems[len(elems)-1] += Hellipsis
+		} else {
+			for i := a.min; i < last; i++ {
+				elems = append(elems, "["+write_arg(a.name_at(i))+"]")
+			}
+
+			elems = append(elems, "["+write_arg(a.name_at(last))+Hellipsis+"]")
+		}
+
+		return strings.Join(elems, " ")
+	}
+
+	if len(a.positionals) == 1 && a.max > 1 && a.min != a.max {
+		// Ranged argument; render every alternative.
+		name := a.positionals[0].Name
+
+		elems := make([]string, 0, a.max-a.min+1)
+
+		for i := max(a.min, 1); i <= a.max; i++ {
+			elems = append(elems, write_n_args(name, i))
+		}
+
+		if a.min == 0 {
+			return "[" + strings.Join(elems, Pipe) + "]"
+		}
+
+		return strings.Join(elems, Pipe)
+	}
+
+	elems := make([]string, 0, a.max)
+
+	for i := 0; i < a.max; i++ {
+		if i < a.min {
+			elems = append(elems, write_arg(a.name_at(i)))
+		} else {
+			elems = append(elems, "["+write_arg(a.name_at(i))+"]")
+		}
 	}
 
 	return strings.Join(elems, " ")
 }
 
+// IsEmpty checks whether the argument accepts no arguments at all.
+//
+// Returns:
+//   - bool: True if the argument accepts no arguments, false otherwise.
+func (a Argument) IsEmpty() bool {
+	return a.max == 0
+}
+
+// Bounds returns the minimum and maximum number of arguments.
+//
+// Returns:
+//   - int: The minimum number of arguments.
+//   - int: The maximum number of arguments. -1 means no maximum.
+func (a Argument) Bounds() (int, int) {
+	return a.min, a.max
+}
+
+// Names returns the names of the positional arguments, in order.
+//
+// Returns:
+//   - []string: The names of the positional arguments.
+func (a Argument) Names() []string {
+	names := make([]string, 0, len(a.positionals))
+
+	for _, pos := range a.positionals {
+		names = append(names, pos.Name)
+	}
+
+	return names
+}
+
+// name_at returns the name of the positional argument used for the argument at
+// the given index.
+//
+// Parameters:
+//   - idx: The 0-based index of the argument.
+//
+// Returns:
+//   - string: The name of the positional argument.
+//
+// Assertions:
+//   - len(a.positionals) > 0
+func (a Argument) name_at(idx int) string {
+	return a.positionals[min(idx, len(a.positionals)-1)].Name
+}
+
+// positional_at returns the positional argument used for the argument at the given
+// index.
+//
+// Parameters:
+//   - idx: The 0-based index of the argument.
+//
+// Returns:
+//   - *Positional: The positional argument. Nil if the argument accepts no argument
+//     at that index.
+func (a Argument) positional_at(idx int) *Positional {
+	if len(a.positionals) == 0 || (a.max != -1 && idx >= a.max) {
+		return nil
+	}
+
+	return a.positionals[min(idx, len(a.positionals)-1)]
+}
+
 // ExactArgs is a helper function that returns an argument with the exact number of arguments.
 //
 // Parameters:
@@ -54,8 +215,135 @@ func (a Argument) String() string {
 // Returns:
 //   - *Argument: The argument with the exact number of arguments. Never returns nil.
 func ExactArgs(args []string) *Argument {
+	positionals := make([]*Positional, 0, len(args))
+
+	for _, name := range args {
+		positionals = append(positionals, StringArg(name))
+	}
+
+	return NewArgument(positionals...)
+}
+
+// NewArgument is a helper function that returns an argument made of exactly the
+// given positional arguments, in order.
+//
+// Parameters:
+//   - positionals: The positional arguments. Nil positional arguments are ignored.
+//
+// Returns:
+//   - *Argument: The argument. Never returns nil.
+func NewArgument(positionals ...*Positional) *Argument {
+	list := filter_positionals(positionals)
+
 	return &Argument{
-		args: args,
+		positionals: list,
+		min:         len(list),
+		max:         len(list),
+	}
+}
+
+// OptionalArgs is a helper function that returns an argument whose first n
+// positional arguments are required while the remaining ones may be omitted.
+// If n is less than 0, it will be set to 0; if it is greater than the number of
+// positional arguments, it will be set to that number.
+//
+// Parameters:
+//   - n: The number of required positional arguments.
+//   - positionals: The positional arguments. Nil positional arguments are ignored.
+//
+// Returns:
+//   - *Argument: The argument. Never returns nil.
+func OptionalArgs(n int, positionals ...*Positional) *Argument {
+	list := filter_positionals(positionals)
+
+	return &Argument{
+		positionals: list,
+		min:         min(max(n, 0), len(list)),
+		max:         len(list),
+	}
+}
+
+// AtLeastNArgs is a helper function that returns an argument that requires at least
+// n arguments, all parsed with the given positional argument.
+// If n is less than 0, it will be set to 0.
+//
+// Parameters:
+//   - pos: The positional argument. If nil, a string argument named "arg" is used.
+//   - n: The minimum number of arguments.
+//
+// Returns:
+//   - *Argument: The argument. Never returns nil.
+func AtLeastNArgs(pos *Positional, n int) *Argument {
+	if pos == nil {
+		pos = StringArg("arg")
+	}
+
+	return &Argument{
+		positionals: []*Positional{pos},
+		min:         max(n, 0),
+		max:         -1,
+	}
+}
+
+// AtMostNArgs is a helper function that returns an argument that requires at most
+// n arguments, all parsed with the given positional argument.
+// If n is 0 or less, NoArguments will be returned instead.
+//
+// Parameters:
+//   - pos: The positional argument. If nil, a string argument named "arg" is used.
+//   - n: The maximum number of arguments.
+//
+// Returns:
+//   - *Argument: The argument. Never returns nil.
+func AtMostNArgs(pos *Positional, n int) *Argument {
+	if n <= 0 {
+		return NoArguments
+	}
+
+	if pos == nil {
+		pos = StringArg("arg")
+	}
+
+	return &Argument{
+		positionals: []*Positional{pos},
+		min:         0,
+		max:         n,
+	}
+}
+
+// BetweenArgs is a helper function that returns an argument that requires between
+// min_count and max_count arguments, all parsed with the given positional argument.
+// If min_count is less than 0, it will be set to 0.
+// If max_count is less than 0, it will be set to 0.
+// If min_count is greater than max_count, they will be swapped.
+//
+// Parameters:
+//   - pos: The positional argument. If nil, a string argument named "arg" is used.
+//   - min_count: The minimum number of arguments.
+//   - max_count: The maximum number of arguments.
+//
+// Returns:
+//   - *Argument: The argument. Never returns nil.
+func BetweenArgs(pos *Positional, min_count, max_count int) *Argument {
+	min_count = max(min_count, 0)
+	max_count = max(max_count, 0)
+
+	if min_count > max_count {
+		min_count, max_count = max_count, min_count
+	}
+
+	if max_count == 0 {
+		return NoArguments
+	}
+
+	if pos == nil {
+		pos = StringArg("arg")
+	}
+
+	return &Argument{
+		positionals: []*Positional{pos},
+		min:         min_count,
+		max:         max_count,
 	}
 }
 
@@ -65,12 +353,90 @@ func ExactArgs(args []string) *Argument {
 //   - args: The arguments to parse.
 //
 // Returns:
-//   - []string: The parsed arguments.
+//   - *ParsedArgs: The parsed arguments. Nil if an error occurred.
 //   - error: An error if the arguments are invalid.
-func (a Argument) parse(args []string) ([]string, error) {
-	if len(a.args) > len(args) {
-		return nil, fmt.Errorf("expected %d arguments, got %d instead", len(a.args), len(args))
+func (a Argument) parse(args []string) (*ParsedArgs, error) {
+	if len(args) < a.min {
+		return nil, NewErrFewArguments(a.min, len(args))
+	} else if a.max != -1 && len(args) > a.max {
+		return nil, NewErrManyArguments(a.max, len(args))
+	}
+
+	pa := &ParsedArgs{
+		raw:    args,
+		values: make(map[string][]any, len(a.positionals)),
+	}
+
+	for i, arg := range args {
+		pos := a.positionals[min(i, len(a.positionals)-1)]
+
+		fn := pos.ParseFn
+		if fn == nil {
+			fn = parse_string
+		}
+
+		value, err := fn(arg)
+		if err != nil {
+			return nil, NewErrInvalidArgument(pos.Name, i+1, arg, err)
+		}
+
+		pa.values[pos.Name] = append(pa.values[pos.Name], value)
+	}
+
+	return pa, nil
+}
+
+// filter_positionals is a helper function that removes nil positional arguments.
+//
+// Parameters:
+//   - positionals: The positional arguments.
+//
+// Returns:
+//   - []*Positional: The non-nil positional arguments.
+func filter_positionals(positionals []*Positional) []*Positional {
+	var list []*Positional
+
+	for _, pos := range positionals {
+		if pos != nil {
+			list = append(list, pos)
+		}
+	}
+
+	return list
+}
+
+// write_arg is a helper function that writes an argument.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - string: The string representation of the argument.
+func write_arg(name string) string {
+	return "<" + name + ">"
+}
+
+// write_n_args is a helper function that writes n arguments.
+//
+// Parameters:
+//   - name: The name of the argument.
+//   - n: The number of arguments.
+//
+// Returns:
+//   - string: The string representation of the arguments.
+//
+// Assertions:
+//   - n >= 0
+func write_n_args(name string, n int) string {
+	if n == 0 {
+		return ""
 	}
 
-	return args[:len(a.args)], nil
+	elems := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		elems = append(elems, write_arg(name))
+	}
+
+	return strings.Join(elems, " ")
 }
@@ -0,0 +1,89 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestArgumentParse(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  *Argument
+		args []string
+		want string
+	}{
+		{"exact", NewArgument(StringArg("name"), IntArg("count")), []string{"bob", "3"}, "map[count:[3] name:[bob]]"},
+		{"optional omitted", OptionalArgs(1, StringArg("name"), IntArg("count")), []string{"bob"}, "map[name:[bob]]"},
+		{"at least", AtLeastNArgs(IntArg("n"), 1), []string{"1", "2", "3"}, "map[n:[1 2 3]]"},
+		{"at most", AtMostNArgs(nil, 2), []string{"x"}, "map[arg:[x]]"},
+		{"between", BetweenArgs(StringArg("file"), 1, 2), []string{"a", "b"}, "map[file:[a b]]"},
+		{"between swapped", BetweenArgs(nil, 2, 1), []string{"x"}, "map[arg:[x]]"},
+		{"between negative", BetweenArgs(nil, -1, 1), nil, "map[]"},
+		{"enum", NewArgument(EnumArg("mode", "hard", "soft")), []string{"soft"}, "map[mode:[soft]]"},
+		{"no arguments", NoArguments, nil, "map[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pa, err := tt.arg.parse(tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := fmt.Sprint(pa.values)
+			if got != tt.want {
+				t.Errorf("values = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgumentParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		arg    *Argument
+		args   []string
+		target any
+	}{
+		{"too few", NewArgument(StringArg("name"), IntArg("count")), []string{"bob"}, new(*ErrFewArguments)},
+		{"too many", NewArgument(StringArg("name")), []string{"bob", "alice"}, new(*ErrManyArguments)},
+		{"at least", AtLeastNArgs(nil, 2), []string{"a"}, new(*ErrFewArguments)},
+		{"at most", AtMostNArgs(nil, 1), []string{"a", "b"}, new(*ErrManyArguments)},
+		{"no arguments", NoArguments, []string{"a"}, new(*ErrManyArguments)},
+		{"invalid int", NewArgument(IntArg("count")), []string{"many"}, new(*ErrInvalidArgument)},
+		{"invalid enum", NewArgument(EnumArg("mode", "hard", "soft")), []string{"mixed"}, new(*ErrInvalidArgument)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.arg.parse(tt.args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if !errors.As(err, tt.target) {
+				t.Errorf("error = %v (%T), want %T", err, err, tt.target)
+			}
+		})
+	}
+}
+
+func TestArgumentString(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  *Argument
+		want string
+	}{
+		{"exact", NewArgument(StringArg("name"), IntArg("count")), "<name> <count>"},
+		{"optional", OptionalArgs(1, StringArg("name"), IntArg("count")), "<name> [<count>]"},
+		{"no arguments", NoArguments, ""},
+	}
+
+	for _, tt := range tests {
+		got := tt.arg.String()
+		if got != tt.want {
+			t.Errorf("String(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package simple
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// MaxBatchDepth is the maximum number of batches that run one inside the other;
+	// such as a script that runs another script with the "run" command.
+	MaxBatchDepth int = 16
+)
+
+// BatchErrorPolicy is the policy of a batch when one of its commands fails.
+type BatchErrorPolicy int
+
+const (
+	// StopOnError stops the batch at the first command that fails.
+	StopOnError BatchErrorPolicy = iota
+
+	// ContinueOnError runs every command of the batch, whatever their outcome.
+	ContinueOnError
+)
+
+// BatchLine is the outcome of one command of a batch.
+type BatchLine struct {
+	// Number is the number of the line of the command, starting at 1. For commands
+	// that span several lines, it is the number of the first one.
+	Number int
+
+	// Text is the text of the command, with its continuations joined.
+	Text string
+
+	// Err is the error of the command. Nil if the command succeeded.
+	Err error
+
+	// ExitCode is the exit code of the command.
+	ExitCode int
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format: "line <number>: ok: <text>" or "line <number>: exit <code>: <error>"
+func (bl BatchLine) String() string {
+	if bl.Err == nil {
+		return fmt.Sprintf("line %d: ok: %s", bl.Number, bl.Text)
+	}
+
+	return fmt.Sprintf("line %d: exit %d: %s", bl.Number, bl.ExitCode, bl.Err.Error())
+}
+
+// BatchResult is the outcome of a batch.
+type BatchResult struct {
+	// Lines is the outcome of every command that ran, in order.
+	Lines []BatchLine
+
+	// ExitCode is the exit code of the batch; that is, the exit code of the first
+	// command that failed. ExitSuccess if none did.
+	ExitCode int
+}
+
+// Failed returns the number of commands that failed.
+//
+// Returns:
+//   - int: The number of commands that failed.
+func (br BatchResult) Failed() int {
+	var count int
+
+	for _, line := range br.Lines {
+		if line.Err != nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Err returns the error of the batch.
+//
+// Returns:
+//   - error: An *ErrBatchFailed if a command failed. Nil otherwise.
+func (br BatchResult) Err() error {
+	failed := br.Failed()
+	if failed == 0 {
+		return nil
+	}
+
+	return NewErrBatchFailed(failed, len(br.Lines), br.ExitCode)
+}
+
+// BatchOptions is the options of a batch.
+type BatchOptions struct {
+	// Policy is the policy of the batch when one of its commands fails.
+	Policy BatchErrorPolicy
+
+	// Report is called after each command ran. Leave nil if not needed.
+	Report func(line BatchLine)
+}
+
+// NewRunCommand creates a command that runs the commands of a script file. (See
+// Program.RunBatch) Every command is reported on the error stream. It is not
+// registered by default; add it with Program.AddCommands.
+//
+// Returns:
+//   - *Command: The command. Never returns nil.
+func NewRunCommand() *Command {
+	cmd := &Command{
+		Name:     "run",
+		Brief:    "Runs the commands of a script file",
+		Argument: NewArgument(PathArg("file")),
+		Examples: []string{"run deploy.lcml", "run --keep-going checks.lcml"},
+	}
+
+	keep_going := cmd.Flags().Bool("keep-going", false, "Runs every command even if some fail", WithShortName('k'))
+
+	cmd.RunFn = func(p *Program, args []string) error {
+		opts := BatchOptions{
+			Report: func(line BatchLine) {
+				_, _ = fmt.Fprintln(p.error_output(), line.String())
+			},
+		}
+
+		if *keep_going {
+			opts.Policy = ContinueOnError
+		}
+
+		res, err := p.RunScript(args[0], opts)
+		if err != nil {
+			return err
+		}
+
+		return res.Err()
+	}
+
+	return cmd
+}
+
+// RunScript runs the commands of the given script file. (See RunBatch)
+//
+// Parameters:
+//   - path: The path of the script.
+//   - opts: The options of the batch.
+//
+// Returns:
+//   - *BatchResult: The outcome of the batch. Nil if an error occurred.
+//   - error: An error if the script could not be read.
+func (p Program) RunScript(path string, opts BatchOptions) (*BatchResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return p.RunBatch(file, opts)
+}
+
+// RunBatch runs the commands read from the given reader against the program, one
+// command per line, as if each were given on the command line:
+//   - blank lines and comments, which start with '#', are skipped;
+//   - a line that ends with a backslash continues on the next line;
+//   - "${NAME}" is replaced by the environment variable NAME, as returned by
+//     Program.Getenv, and "${NAME:-default}" by the default if NAME is not set;
+//   - words are split as SplitLine does.
+//
+// The batch stops at the first command that fails, unless opts.Policy is
+// ContinueOnError. Whatever the policy, it stops once the context of the program is
+// cancelled: the next command is not run and fails with the cause of the
+// cancellation instead. (i.e., an *ErrInterrupted) Missing arguments are never
+// prompted for.
+//
+// Parameters:
+//   - r: The reader of the commands.
+//   - opts: The options of the batch.
+//
+// Returns:
+//   - *BatchResult: The outcome of the batch. Nil if an error occurred.
+//   - error: An error if the reader failed or if more than MaxBatchDepth batches run
+//     one inside the other.
+func (p Program) RunBatch(r io.Reader, opts BatchOptions) (*BatchResult, error) {
+	if p.batch_depth >= MaxBatchDepth {
+		return nil, fmt.Errorf("scripts cannot run more than %d levels deep", MaxBatchDepth)
+	}
+
+	p.batch_depth++
+
+	// A command of the batch must fail rather than wait for the user.
+	p.NoPrompt = true
+
+	expand := func(name string) (string, error) {
+		value, ok := p.lookup_env(name)
+		if !ok {
+			return "", fmt.Errorf("variable %q is not set", name)
+		}
+
+		return value, nil
+	}
+
+	res := &BatchResult{
+		ExitCode: ExitSuccess,
+	}
+
+	scanner := bufio.NewScanner(r)
+
+	var number int
+
+	ctx := p.Context()
+
+	for {
+		text, first, ok := scan_logical_line(scanner, &number)
+		if !ok {
+			break
+		}
+
+		words, err := split_line(text, expand)
+
+		if err == nil && len(words) == 0 {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			err = context.Cause(ctx)
+		} else if err == nil {
+			err = p.Run(append([]string{p.Name}, words...))
+		}
+
+		line := BatchLine{
+			Number:   first,
+			Text:     strings.TrimSpace(text),
+			Err:      err,
+			ExitCode: p.ExitCode(err),
+		}
+
+		res.Lines = append(res.Lines, line)
+
+		if opts.Report != nil {
+			opts.Report(line)
+		}
+
+		if err != nil {
+			if res.ExitCode == ExitSuccess {
+				res.ExitCode = line.ExitCode
+			}
+
+			if opts.Policy == StopOnError || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// scan_logical_line scans the next line, joining the lines that end with an odd
+// number of backslashes with the line that follows them.
+//
+// Parameters:
+//   - scanner: The scanner of the lines.
+//   - number: The number of the last scanned line. It is updated.
+//
+// Returns:
+//   - string: The line, with its continuations joined.
+//   - int: The number of the first line.
+//   - bool: False if there are no more lines.
+func scan_logical_line(scanner *bufio.Scanner, number *int) (string, int, bool) {
+	var builder strings.Builder
+
+	first := *number + 1
+
+	for scanner.Scan() {
+		*number++
+
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+
+		trimmed := strings.TrimRight(text, "\\")
+		if (len(text)-len(trimmed))%2 == 0 {
+			builder.WriteString(text)
+
+			return builder.String(), first, true
+		}
+
+		builder.WriteString(text[:len(text)-1])
+		builder.WriteRune(' ')
+	}
+
+	if builder.Len() == 0 {
+		return "", 0, false
+	}
+
+	return builder.String(), first, true
+}
@@ -0,0 +1,252 @@
+package simple
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// new_test_batch_program creates the program used by the tests of the batches. Its
+// "echo" command writes its arguments to out, "fail" fails and "cancel" cancels the
+// context of the program with an *ErrInterrupted for SIGINT.
+func new_test_batch_program(out *bytes.Buffer, env map[string]string) (*Program, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	p := &Program{
+		Name: "tool",
+		Out:  out,
+		Err:  &bytes.Buffer{},
+		LookupEnv: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+		ctx: ctx,
+	}
+
+	p.AddCommands(
+		&Command{
+			Name:     "echo",
+			Argument: AtLeastNArgs(StringArg("word"), 0),
+			RunFn: func(p *Program, args []string) error {
+				_, err := fmt.Fprintln(p.output(), strings.Join(args, "|"))
+				return err
+			},
+		},
+		&Command{
+			Name:  "fail",
+			RunFn: func(_ *Program, _ []string) error { return errors.New("boom") },
+		},
+		&Command{
+			Name: "cancel",
+			RunFn: func(_ *Program, _ []string) error {
+				cancel(NewErrInterrupted(syscall.SIGINT))
+				return nil
+			},
+		},
+		NewRunCommand(),
+	)
+
+	return p, cancel
+}
+
+func TestRunBatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		policy BatchErrorPolicy
+		lines  []string
+		out    string
+		code   int
+	}{
+		{
+			name:   "comments and blank lines",
+			script: "# a comment\n\n   \n  # an indented comment\necho a # a trailing comment\n",
+			lines:  []string{"line 5: ok: echo a # a trailing comment"},
+			out:    "a\n",
+		},
+		{
+			name:   "continuation",
+			script: "echo a \\\n  b\\\nc\necho d\n",
+			lines:  []string{"line 1: ok: echo a    b c", "line 4: ok: echo d"},
+			out:    "a|b|c\nd\n",
+		},
+		{
+			name:   "escaped backslash",
+			script: "echo 'a\\\\'\necho b\n",
+			lines:  []string{`line 1: ok: echo 'a\\'`, "line 2: ok: echo b"},
+			out:    "a\\\\\nb\n",
+		},
+		{
+			name:   "continuation at the end",
+			script: "echo a \\",
+			lines:  []string{"line 1: ok: echo a"},
+			out:    "a\n",
+		},
+		{
+			name:   "variables",
+			script: "echo ${NAME} \"${NAME}s\" ${MISSING:-def} ${NAME:-def} '${NAME}'\n",
+			lines:  []string{`line 1: ok: echo ${NAME} "${NAME}s" ${MISSING:-def} ${NAME:-def} '${NAME}'`},
+			out:    "value|values|def|value|${NAME}\n",
+		},
+		{
+			name:   "unset variable",
+			script: "echo ${MISSING}\necho b\n",
+			lines:  []string{`line 1: exit 1: variable "MISSING" is not set`},
+			code:   ExitFailure,
+		},
+		{
+			name:   "stop on error",
+			script: "echo a\nfail\necho b\n",
+			lines:  []string{"line 1: ok: echo a", `line 2: exit 1: command "fail" failed: boom`},
+			out:    "a\n",
+			code:   ExitFailure,
+		},
+		{
+			name:   "continue on error",
+			script: "echo a\nunknown\nfail\necho b\n",
+			policy: ContinueOnError,
+			lines: []string{
+				"line 1: ok: echo a",
+				`line 2: exit 2: command "unknown" not found`,
+				`line 3: exit 1: command "fail" failed: boom`,
+				"line 4: ok: echo b",
+			},
+			out:  "a\nb\n",
+			code: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			p, _ := new_test_batch_program(&out, map[string]string{"NAME": "value"})
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var reported []string
+
+			res, err := p.RunBatch(strings.NewReader(tt.script), BatchOptions{
+				Policy: tt.policy,
+				Report: func(line BatchLine) { reported = append(reported, line.String()) },
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var lines []string
+
+			for _, line := range res.Lines {
+				lines = append(lines, line.String())
+			}
+
+			if strings.Join(lines, "\n") != strings.Join(tt.lines, "\n") {
+				t.Errorf("lines:\n  %s\nwant:\n  %s", strings.Join(lines, "\n  "), strings.Join(tt.lines, "\n  "))
+			}
+
+			if strings.Join(reported, "\n") != strings.Join(lines, "\n") {
+				t.Errorf("reported lines %q, want %q", reported, lines)
+			}
+
+			if out.String() != tt.out {
+				t.Errorf("output = %q, want %q", out.String(), tt.out)
+			}
+
+			if res.ExitCode != tt.code {
+				t.Errorf("exit code = %d, want %d", res.ExitCode, tt.code)
+			}
+
+			var failed *ErrBatchFailed
+
+			if errors.As(res.Err(), &failed) != (tt.code != ExitSuccess) {
+				t.Errorf("Err() = %v, want an *ErrBatchFailed: %t", res.Err(), tt.code != ExitSuccess)
+			}
+		})
+	}
+}
+
+func TestRunBatchCancelled(t *testing.T) {
+	for _, policy := range []BatchErrorPolicy{StopOnError, ContinueOnError} {
+		var out bytes.Buffer
+
+		p, cancel := new_test_batch_program(&out, nil)
+		defer cancel(nil)
+
+		err := p.Fix()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		res, err := p.RunBatch(strings.NewReader("echo a\ncancel\necho b\necho c\n"), BatchOptions{Policy: policy})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out.String() != "a\n" {
+			t.Errorf("policy %d: output = %q, want %q", policy, out.String(), "a\n")
+		}
+
+		if len(res.Lines) != 3 {
+			t.Fatalf("policy %d: %d lines, want 3", policy, len(res.Lines))
+		}
+
+		var interrupted *ErrInterrupted
+
+		if !errors.As(res.Lines[2].Err, &interrupted) {
+			t.Errorf("policy %d: error of line 3 = %v, want an *ErrInterrupted", policy, res.Lines[2].Err)
+		}
+
+		if res.ExitCode != 130 {
+			t.Errorf("policy %d: exit code = %d, want 130", policy, res.ExitCode)
+		}
+
+		if res.Err() == nil {
+			t.Errorf("policy %d: an interrupted batch succeeded", policy)
+		}
+	}
+}
+
+func TestRunScriptNested(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "self.lcml")
+
+	err := os.WriteFile(path, []byte("run '"+path+"'\n"), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+
+	p, cancel := new_test_batch_program(&out, nil)
+	defer cancel(nil)
+
+	err = p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	res, err := p.RunScript(path, BatchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res.Lines) != 1 || res.Lines[0].Err == nil {
+		t.Fatalf("lines = %v, want a single failed line", res.Lines)
+	}
+
+	// Every level reports its lines on the error stream; the innermost one could not
+	// start.
+	report := p.Err.(*bytes.Buffer).String()
+
+	if !strings.Contains(report, "scripts cannot run more than 16 levels deep") {
+		t.Errorf("report %q does not limit the depth of the scripts", report)
+	}
+}
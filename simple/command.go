@@ -2,20 +2,90 @@ package simple
 
 import (
 	"fmt"
+	"iter"
+	"slices"
+	"strconv"
 	"strings"
 
 	gcers "github.com/PlayerR9/errors"
 )
 
+// CmdRunFn is the function that runs a command.
+//
+// Parameters:
+//   - p: The program that runs the command. The typed values of the arguments are
+//     available through p.Args().
+//   - args: The positional arguments of the command, as strings.
+//
+// Returns:
+//   - error: An error if the command failed.
 type CmdRunFn func(p *Program, args []string) error
 
+// Command is a struct that represents a command.
 type Command struct {
-	Name     string
-	Brief    string
-	RunFn    CmdRunFn
+	// Name is the name of the command.
+	Name string
+
+	// Aliases is the list of alternative names of the command. (i.e., "rm" for "remove")
+	Aliases []string
+
+	// Brief is the brief of the command. Leave empty if not needed.
+	Brief string
+
+	// RunFn is the function that runs the command. If both RunFn and RunContextFn
+	// are nil, a function that does nothing is used; unless the command has
+	// sub-commands, in which case an *ErrNoCommand is returned.
+	RunFn CmdRunFn
+
+	// RunContextFn is the context-aware variant of RunFn; it receives the context of
+	// Program.Context(). Mutually exclusive with RunFn.
+	RunContextFn CmdRunContextFn
+
+	// Argument is the argument of the command. If nil, NoArguments will be used.
 	Argument *Argument
+
+	// Examples is the list of example invocations of the command, without the
+	// program's name. (i.e., "remote add origin https://example.com/repo.git")
+	Examples []string
+
+	// Group is the heading under which the command is listed in the help. Commands
+	// with no group are listed under "Commands".
+	Group string
+
+	// Hidden is true if the command is omitted from listings and completions. Hidden
+	// commands can still be run.
+	Hidden bool
+
+	// Deprecated is the deprecation metadata of the command. Nil if the command is
+	// not deprecated.
+	Deprecated *Deprecation
+
+	// NoPrompt is true if the missing arguments of the command are never prompted
+	// for, even when the program is interactive. (See Program.NoPrompt)
+	NoPrompt bool
+
+	// Hooks is the set of hooks that surround the run function of the command and
+	// of all of its sub-commands.
+	Hooks
+
+	// builtin is true if the command was added by the program itself, such as the
+	// help command. (See Command.is_builtin)
+	builtin bool
+
+	// parent is the command that owns this command. Nil for top-level commands.
+	parent *Command
+
+	// sub_commands is the table of sub-commands.
+	sub_commands map[string]*Command
+
+	// sub_order is the names of the sub-commands, in order of registration.
+	sub_order []string
+
+	// flag_set is the set of flags of the command. Nil if the command has no flags.
+	flag_set *FlagSet
 }
 
+// Fix implements the errors.Fixer interface.
 func (c *Command) Fix() error {
 	if c == nil {
 		return nil
@@ -28,14 +98,35 @@ func (c *Command) Fix() error {
 
 	c.Name = name
 
-	c.Brief = strings.TrimSpace(c.Brief)
+	var aliases []string
 
-	if c.RunFn == nil {
-		c.RunFn = func(_ *Program, _ []string) error {
-			return nil
+	for _, alias := range c.Aliases {
+		alias = strings.TrimSpace(alias)
+
+		if alias != "" && alias != name && !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
 		}
 	}
 
+	c.Aliases = aliases
+
+	c.Brief = strings.TrimSpace(c.Brief)
+	c.Group = strings.TrimSpace(c.Group)
+
+	if c.RunContextFn != nil && c.RunFn != nil {
+		return fmt.Errorf("RunFn and RunContextFn are mutually exclusive")
+	}
+
+	err := gcers.Fix("deprecation", c.Deprecated, true)
+	if err != nil {
+		return err
+	}
+
+	err = gcers.Fix("hooks", &c.Hooks, false)
+	if err != nil {
+		return err
+	}
+
 	if c.Argument == nil {
 		c.Argument = NoArguments
 	} else {
@@ -45,9 +136,310 @@ func (c *Command) Fix() error {
 		}
 	}
 
+	for _, k := range c.sub_order {
+		sub := c.sub_commands[k]
+
+		err := gcers.Fix("sub-command "+strconv.Quote(k), sub, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = check_collisions(c.sub_commands)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (c Command) parse(args []string) ([]string, error) {
+// AddCommands adds sub-commands to the command.
+//
+// Parameters:
+//   - commands: The sub-commands to add.
+//
+// Nil commands are ignored.
+func (c *Command) AddCommands(commands ...*Command) {
+	if c == nil || len(commands) == 0 {
+		return
+	}
+
+	if c.sub_commands == nil {
+		c.sub_commands = make(map[string]*Command)
+	}
+
+	for _, command := range commands {
+		if command == nil {
+			continue
+		}
+
+		command.parent = c
+		c.sub_order = add_command(c.sub_commands, c.sub_order, command)
+	}
+}
+
+// Flags returns the set of flags of the command, creating it if needed. Flags are
+// registered through it. (i.e., cmd.Flags().Bool("verbose", false, "..."))
+//
+// Returns:
+//   - *FlagSet: The set of flags. Nil only if the receiver is nil.
+func (c *Command) Flags() *FlagSet {
+	if c == nil {
+		return nil
+	}
+
+	if c.flag_set == nil {
+		c.flag_set = NewFlagSet()
+	}
+
+	return c.flag_set
+}
+
+// HasFlags checks whether the command has at least one flag.
+//
+// Returns:
+//   - bool: True if the command has flags, false otherwise.
+func (c Command) HasFlags() bool {
+	return c.flag_set != nil && c.flag_set.Size() > 0
+}
+
+// HasCommand checks if the command has a sub-command with the given name or alias.
+//
+// Returns:
+//   - bool: True if the command has a sub-command with the given name, false otherwise.
+func (c Command) HasCommand(name string) bool {
+	return has_name(c.sub_commands, name)
+}
+
+// RetrieveCommand retrieves the sub-command with the given name or alias.
+//
+// Returns:
+//   - *Command: The sub-command with the given name. Nil if not found.
+//   - bool: True if the command has a sub-command with the given name, false otherwise.
+func (c Command) RetrieveCommand(name string) (*Command, bool) {
+	cmd, _ := lookup_command(c.sub_commands, name, false)
+	if cmd == nil {
+		return nil, false
+	}
+
+	return cmd, true
+}
+
+// SubCommands is a method that returns an iterator of sub-commands, in order of
+// registration.
+//
+// Returns:
+//   - iter.Seq2[string, *Command]: The iterator of sub-commands.
+func (c Command) SubCommands() iter.Seq2[string, *Command] {
+	return func(yield func(string, *Command) bool) {
+		for _, k := range c.sub_order {
+			if !yield(k, c.sub_commands[k]) {
+				break
+			}
+		}
+	}
+}
+
+// IsHidden checks whether the command is omitted from listings and completions.
+//
+// Returns:
+//   - bool: True if the command is hidden, false otherwise.
+func (c Command) IsHidden() bool {
+	return c.Hidden
+}
+
+// is_builtin checks whether the command, or the top-level command it belongs to, was
+// added by the program itself.
+//
+// Returns:
+//   - bool: True if the command is built-in, false otherwise.
+func (c Command) is_builtin() bool {
+	root := &c
+
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	return root.builtin
+}
+
+// run_fn returns the function that runs the command. When the command has neither
+// RunFn nor RunContextFn, the choice between doing nothing and returning an
+// *ErrNoCommand is made here, with the sub-commands the command has at run time.
+//
+// Returns:
+//   - CmdRunFn: The run function. Never returns nil.
+func (c Command) run_fn() CmdRunFn {
+	if c.RunContextFn != nil {
+		run_ctx_fn := c.RunContextFn
+
+		return func(p *Program, args []string) error {
+			return run_ctx_fn(p.Context(), p, args)
+		}
+	}
+
+	if c.RunFn != nil {
+		return c.RunFn
+	}
+
+	if len(c.sub_commands) == 0 {
+		return func(_ *Program, _ []string) error {
+			return nil
+		}
+	}
+
+	full_name := c.FullName()
+
+	return func(_ *Program, _ []string) error {
+		return NewErrNoCommand(full_name)
+	}
+}
+
+// IsDeprecated checks whether the command is deprecated.
+//
+// Returns:
+//   - bool: True if the command is deprecated, false otherwise.
+func (c Command) IsDeprecated() bool {
+	return c.Deprecated != nil
+}
+
+// Parent returns the command that owns this command.
+//
+// Returns:
+//   - *Command: The parent command. Nil if the command is a top-level command.
+func (c Command) Parent() *Command {
+	return c.parent
+}
+
+// FullName returns the full path of the command; that is, the names of all its
+// ancestors and its own name separated by spaces. (i.e., "remote add")
+//
+// Returns:
+//   - string: The full name of the command.
+func (c Command) FullName() string {
+	names := []string{c.Name}
+
+	for parent := c.parent; parent != nil; parent = parent.parent {
+		names = append(names, parent.Name)
+	}
+
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+
+	return strings.Join(names, " ")
+}
+
+// Usage returns the usage of the command; that is, its full name followed by the
+// usage of its flags and argument. (i.e., "remote add [flags] <name>")
+//
+// Returns:
+//   - string: The usage of the command.
+func (c Command) Usage() string {
+	elems := []string{c.FullName()}
+
+	if c.HasFlags() {
+		elems = append(elems, "[flags]")
+	}
+
+	arg := c.ArgumentUsage()
+	if arg != "" {
+		elems = append(elems, arg)
+	}
+
+	return strings.Join(elems, " ")
+}
+
+// ArgumentUsage returns the usage of the command's argument. Commands that have
+// sub-commands but take no arguments use "<cmd>" instead.
+//
+// Returns:
+//   - string: The usage of the argument. Empty if the command takes no arguments.
+func (c Command) ArgumentUsage() string {
+	if c.Argument == nil || c.Argument.IsEmpty() {
+		if len(c.sub_commands) > 0 {
+			return "<cmd>"
+		}
+
+		return ""
+	}
+
+	return c.Argument.String()
+}
+
+// resolve walks the given arguments down the tree of sub-commands and returns the
+// deepest matching command.
+//
+// Parameters:
+//   - args: The arguments that follow the command's name.
+//   - allow_prefix: Whether an unambiguous prefix resolves to a sub-command.
+//
+// Returns:
+//   - *Command: The deepest matching command. Never returns nil.
+//   - []string: The arguments that follow the deepest matching command.
+//   - error: An *ErrAmbiguousCommand if a prefix matches more than one sub-command.
+func (c *Command) resolve(args []string, allow_prefix bool) (*Command, []string, error) {
+	cmd := c
+
+	for len(args) > 0 {
+		sub, err := lookup_command(cmd.sub_commands, args[0], allow_prefix)
+		if err != nil {
+			return nil, nil, err
+		} else if sub == nil {
+			break
+		}
+
+		cmd = sub
+		args = args[1:]
+	}
+
+	return cmd, args, nil
+}
+
+// parse parses the flags and the argument of the command. Flags and positional
+// arguments that are not given on the command line fall back to their environment
+// variable, if any, then to the settings of the configuration files and then to
+// their default. Required arguments that are still missing are prompted for when
+// the program is interactive.
+//
+// Parameters:
+//   - p: The program that runs the command.
+//   - args: The arguments that follow the command's name.
+//
+// Returns:
+//   - *ParsedArgs: The parsed positional arguments. Nil if an error occurred.
+//   - error: An error if the flags or the arguments are invalid.
+func (c *Command) parse(p *Program, args []string) (*ParsedArgs, error) {
+	if c.flag_set != nil {
+		var err error
+
+		args, err = c.flag_set.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.apply_env_flags(c)
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.apply_config_flags(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	args, err := p.fallback_args(c, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) < c.Argument.min && p.can_prompt(c) {
+		args, err = p.prompt_args(c, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return c.Argument.parse(args)
 }
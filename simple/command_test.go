@@ -0,0 +1,121 @@
+package simple
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// new_test_command_tree creates the tree of commands used by the tests of this
+// file: "remote" with the sub-commands "add" (alias "new"), "remove", "rename" and the
+// hidden "prune"; "add" has the sub-command "mirror".
+func new_test_command_tree() *Command {
+	add := &Command{Name: "add", Aliases: []string{"new"}}
+	add.AddCommands(&Command{Name: "mirror"})
+
+	remote := &Command{Name: "remote"}
+	remote.AddCommands(add, &Command{Name: "remove"}, &Command{Name: "rename"}, &Command{Name: "prune", Hidden: true})
+
+	return remote
+}
+
+func TestCommandResolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		prefix bool
+		want   string
+		rest   []string
+	}{
+		{"no arguments", nil, false, "remote", nil},
+		{"sub-command", []string{"add", "origin"}, false, "remote add", []string{"origin"}},
+		{"nested", []string{"add", "mirror", "x"}, false, "remote add mirror", []string{"x"}},
+		{"alias", []string{"new", "mirror"}, false, "remote add mirror", []string{}},
+		{"not a sub-command", []string{"origin", "add"}, false, "remote", []string{"origin", "add"}},
+		{"prefix off", []string{"mir"}, false, "remote", []string{"mir"}},
+		{"prefix", []string{"add", "mir"}, true, "remote add mirror", []string{}},
+		{"hidden needs its full name", []string{"pru"}, true, "remote", []string{"pru"}},
+		{"hidden by its full name", []string{"prune"}, true, "remote prune", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, rest, err := new_test_command_tree().resolve(tt.args, tt.prefix)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if cmd.FullName() != tt.want {
+				t.Errorf("command = %q, want %q", cmd.FullName(), tt.want)
+			}
+
+			if len(rest) != len(tt.rest) || !slices.Equal(rest, tt.rest) {
+				t.Errorf("rest = %q, want %q", rest, tt.rest)
+			}
+		})
+	}
+
+	_, _, err := new_test_command_tree().resolve([]string{"re"}, true)
+
+	var ambiguous *ErrAmbiguousCommand
+
+	if !errors.As(err, &ambiguous) {
+		t.Errorf("resolve(\"re\"): error = %v, want an *ErrAmbiguousCommand", err)
+	}
+}
+
+func TestCommandFullName(t *testing.T) {
+	remote := new_test_command_tree()
+	add, _ := remote.RetrieveCommand("add")
+	mirror, _ := add.RetrieveCommand("mirror")
+
+	tests := []struct {
+		cmd  *Command
+		want string
+	}{
+		{remote, "remote"},
+		{add, "remote add"},
+		{mirror, "remote add mirror"},
+	}
+
+	for _, tt := range tests {
+		got := tt.cmd.FullName()
+		if got != tt.want {
+			t.Errorf("FullName(%q) = %q, want %q", tt.cmd.Name, got, tt.want)
+		}
+	}
+}
+
+func TestCommandRunFnWithoutFunction(t *testing.T) {
+	cmd := &Command{Name: "remote"}
+
+	err := cmd.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = cmd.run_fn()(nil, nil)
+	if err != nil {
+		t.Errorf("leaf command: unexpected error: %v", err)
+	}
+
+	// Sub-commands added after Fix must still be required.
+	cmd.AddCommands(&Command{Name: "add"})
+
+	err = cmd.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cmd.RunFn != nil {
+		t.Error("Fix must not set RunFn")
+	}
+
+	err = cmd.run_fn()(nil, nil)
+
+	var no_command *ErrNoCommand
+
+	if !errors.As(err, &no_command) || no_command.Command != "remote" {
+		t.Errorf("error = %v, want an *ErrNoCommand for \"remote\"", err)
+	}
+}
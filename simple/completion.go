@@ -0,0 +1,310 @@
+package simple
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// CompleteCmdName is the name of the hidden command that the completion scripts
+	// call back into.
+	CompleteCmdName string = "__complete"
+)
+
+// CompleteFunc is a function that lists the candidate values of a positional
+// argument.
+//
+// Parameters:
+//   - p: The program being completed.
+//   - prefix: The partial word being completed.
+//
+// Returns:
+//   - []string: The candidate values. Values that do not start with prefix are
+//     discarded.
+type CompleteFunc func(p *Program, prefix string) []string
+
+// new_completion_command creates the built-in completion command.
+//
+// Returns:
+//   - *Command: The completion command. Never returns nil.
+func new_completion_command() *Command {
+	pos := EnumArg("shell", "bash", "zsh", "fish", "powershell")
+	pos.Env = EnvNone
+
+	return &Command{
+		Name:  "completion",
+		Brief: "Prints the completion script of the program for the given shell",
+		RunFn: func(p *Program, args []string) error {
+			return p.GenCompletion(p, args[0])
+		},
+		Argument: NewArgument(pos),
+		builtin:  true,
+	}
+}
+
+// new_complete_command creates the hidden command used by the completion scripts.
+//
+// Returns:
+//   - *Command: The hidden command. Never returns nil.
+func new_complete_command() *Command {
+	pos := StringArg("word")
+	pos.Env = EnvNone
+
+	return &Command{
+		Name:  CompleteCmdName,
+		Brief: "Prints the candidates of the last word; used by the completion scripts",
+		RunFn: func(p *Program, args []string) error {
+			for _, candidate := range p.Complete(args) {
+				_, err := fmt.Fprintln(p, candidate)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Argument: AtLeastNArgs(pos, 0),
+		Hidden:   true,
+		builtin:  true,
+	}
+}
+
+// Complete lists the candidates of the last of the given words.
+//
+// Parameters:
+//   - words: The words of the command line, without the program's name. The last
+//     word is the partial word being completed and may be empty.
+//
+// Returns:
+//   - []string: The sorted candidates.
+func (p Program) Complete(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	prefix := words[len(words)-1]
+	done := words[:len(words)-1]
+
+	if len(done) == 0 {
+		var candidates []string
+
+		for name, cmd := range p.command_table {
+			if !cmd.Hidden {
+				candidates = append(candidates, name)
+			}
+		}
+
+		return filter_candidates(candidates, prefix)
+	}
+
+	cmd, err := lookup_command(p.command_table, done[0], p.AllowPrefix)
+	if err != nil || cmd == nil {
+		return nil
+	}
+
+	cmd, rest, err := cmd.resolve(done[1:], p.AllowPrefix)
+	if err != nil {
+		return nil
+	}
+
+	if strings.HasPrefix(prefix, ShortFlagPrefix) {
+		if !cmd.HasFlags() {
+			return nil
+		}
+
+		var candidates []string
+
+		for flag := range cmd.flag_set.Flags() {
+			candidates = append(candidates, LongFlagPrefix+flag.long_name)
+
+			if flag.short_name != 0 {
+				candidates = append(candidates, ShortFlagPrefix+string(flag.short_name))
+			}
+		}
+
+		return filter_candidates(candidates, prefix)
+	}
+
+	var candidates []string
+
+	if len(rest) == 0 {
+		for name, sub := range cmd.sub_commands {
+			if !sub.Hidden {
+				candidates = append(candidates, name)
+			}
+		}
+	}
+
+	pos := cmd.Argument.positional_at(count_positionals(cmd.flag_set, rest))
+	if pos != nil && pos.CompleteFn != nil {
+		candidates = append(candidates, pos.CompleteFn(&p, prefix)...)
+	}
+
+	return filter_candidates(candidates, prefix)
+}
+
+// count_positionals counts the positional arguments among the given words.
+//
+// Parameters:
+//   - fs: The flags of the command. May be nil.
+//   - words: The words that follow the command's name.
+//
+// Returns:
+//   - int: The number of positional arguments.
+func count_positionals(fs *FlagSet, words []string) int {
+	var count int
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+
+		if word == FlagTerminator {
+			return count + len(words) - i - 1
+		}
+
+		if !strings.HasPrefix(word, ShortFlagPrefix) || word == ShortFlagPrefix || is_number(word) {
+			count++
+			continue
+		}
+
+		if fs == nil || strings.Contains(word, "=") {
+			continue
+		}
+
+		var flag *Flag
+
+		if strings.HasPrefix(word, LongFlagPrefix) {
+			flag = fs.long_flag(word[len(LongFlagPrefix):])
+		} else {
+			runes := []rune(word[len(ShortFlagPrefix):])
+
+			if len(runes) > 0 {
+				flag = fs.short_flag(runes[len(runes)-1])
+			}
+		}
+
+		if flag != nil && !flag.IsBool() {
+			// Skip the value of the flag.
+			i++
+		}
+	}
+
+	return count
+}
+
+// filter_candidates keeps the unique candidates that start with the given prefix.
+//
+// Parameters:
+//   - candidates: The candidates to filter.
+//   - prefix: The prefix.
+//
+// Returns:
+//   - []string: The sorted candidates.
+func filter_candidates(candidates []string, prefix string) []string {
+	var result []string
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			result = append(result, candidate)
+		}
+	}
+
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
+
+// GenCompletion writes the completion script of the program for the given shell.
+//
+// Parameters:
+//   - w: The writer to write the script to.
+//   - shell: The shell. One of "bash", "zsh", "fish" or "powershell".
+//
+// Returns:
+//   - error: An error if the shell is not supported or the script could not be
+//     written.
+func (p Program) GenCompletion(w io.Writer, shell string) error {
+	var script string
+
+	switch shell {
+	case "bash":
+		script = bash_completion
+	case "zsh":
+		script = zsh_completion
+	case "fish":
+		script = fish_completion
+	case "powershell":
+		script = powershell_completion
+	default:
+		return fmt.Errorf("shell %q is not supported", shell)
+	}
+
+	replacer := strings.NewReplacer(
+		"{{NAME}}", p.Name,
+		"{{FN}}", function_name(p.Name),
+		"{{COMPLETE}}", CompleteCmdName,
+	)
+
+	_, err := io.WriteString(w, replacer.Replace(script))
+	return err
+}
+
+// function_name turns the given program name into a valid shell function name.
+//
+// Parameters:
+//   - name: The name of the program.
+//
+// Returns:
+//   - string: The function name.
+func function_name(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+
+		return '_'
+	}, name)
+}
+
+const bash_completion string = `# bash completion for {{NAME}}
+_{{FN}}_complete() {
+    local IFS=$'\n'
+    COMPREPLY=($({{NAME}} {{COMPLETE}} "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _{{FN}}_complete {{NAME}}
+`
+
+const zsh_completion string = `#compdef {{NAME}}
+_{{FN}}() {
+    local -a completions
+    completions=("${(@f)$({{NAME}} {{COMPLETE}} "${(@)words[2,$CURRENT]}" 2>/dev/null)}")
+    compadd -a completions
+}
+compdef _{{FN}} {{NAME}}
+`
+
+const fish_completion string = `# fish completion for {{NAME}}
+function __{{FN}}_complete
+    set -l tokens (commandline -opc)
+    set -l current (commandline -ct)
+    {{NAME}} {{COMPLETE}} $tokens[2..-1] "$current" 2>/dev/null
+end
+complete -c {{NAME}} -f -a '(__{{FN}}_complete)'
+`
+
+const powershell_completion string = `# PowerShell completion for {{NAME}}
+Register-ArgumentCompleter -Native -CommandName '{{NAME}}' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') {
+        $words += '""'
+    }
+
+    & '{{NAME}}' {{COMPLETE}} @words 2>$null | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
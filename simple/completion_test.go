@@ -0,0 +1,152 @@
+package simple
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// new_test_completion_program creates the program used by the tests of the
+// completion.
+func new_test_completion_program(out, err_out *bytes.Buffer) *Program {
+	branch := StringArg("branch")
+	branch.CompleteFn = func(_ *Program, _ string) []string {
+		return []string{"main", "master", "dev"}
+	}
+
+	checkout := &Command{
+		Name:     "checkout",
+		Argument: NewArgument(branch, EnumArg("mode", "hard", "soft")),
+	}
+
+	checkout.Flags().Bool("force", false, "Force", WithShortName('f'))
+	checkout.Flags().String("track", "", "Track", WithShortName('t'))
+
+	remote := &Command{Name: "remote"}
+	remote.AddCommands(
+		&Command{Name: "add", Argument: NewArgument(StringArg("name"))},
+		&Command{Name: "remove"},
+		&Command{Name: "prune", Hidden: true},
+	)
+
+	p := &Program{
+		Name:    "tool",
+		Version: "1.0.0",
+		Out:     out,
+		Err:     err_out,
+	}
+
+	p.AddCommands(checkout, remote, &Command{Name: "secret", Hidden: true})
+
+	return p
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{"commands", []string{""}, []string{"checkout", "completion", "help", "remote", "version"}},
+		{"command prefix", []string{"re"}, []string{"remote"}},
+		{"sub-commands", []string{"remote", ""}, []string{"add", "remove"}},
+		{"dynamic completer", []string{"checkout", "ma"}, []string{"main", "master"}},
+		{"second positional", []string{"checkout", "main", ""}, []string{"hard", "soft"}},
+		{"flag value is skipped", []string{"checkout", "-t", "origin", "m"}, []string{"main", "master"}},
+		{"bool flag is not skipped", []string{"checkout", "-f", "main", "s"}, []string{"soft"}},
+		{"flags", []string{"checkout", "--"}, []string{"--force", "--track"}},
+		{"unknown command", []string{"nope", ""}, nil},
+		{"no completer", []string{"remote", "add", ""}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, err_out bytes.Buffer
+
+			p := new_test_completion_program(&out, &err_out)
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := p.Complete(tt.words)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Complete(%q) = %q, want %q", tt.words, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionOutputIsClean(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want func(out string) bool
+	}{
+		{"script", []string{"tool", "completion", "bash"}, func(out string) bool {
+			return strings.HasPrefix(out, "# bash completion for tool\n")
+		}},
+		{"candidates", []string{"tool", CompleteCmdName, "remote", "a"}, func(out string) bool {
+			return out == "add\n"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, err_out bytes.Buffer
+
+			p := new_test_completion_program(&out, &err_out)
+
+			var code int
+
+			p.ExitPolicy = &ExitPolicy{
+				Pause:  PauseAuto,
+				ExitFn: func(c int) { code = c },
+			}
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			p.ExitSequence(p.Run(tt.args))
+
+			if code != ExitSuccess {
+				t.Errorf("exit code = %d, want %d", code, ExitSuccess)
+			}
+
+			if !tt.want(out.String()) {
+				t.Errorf("unexpected output %q", out.String())
+			}
+
+			if strings.Contains(out.String(), DefaultSuccessMessage) {
+				t.Errorf("output %q contains the success message", out.String())
+			}
+
+			if !strings.Contains(err_out.String(), DefaultSuccessMessage) {
+				t.Errorf("error output %q does not contain the success message", err_out.String())
+			}
+		})
+	}
+}
+
+func TestGenCompletion(t *testing.T) {
+	p := Program{Name: "my-tool"}
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var out bytes.Buffer
+
+		err := p.GenCompletion(&out, shell)
+		if err != nil {
+			t.Errorf("GenCompletion(%q): unexpected error: %v", shell, err)
+		} else if !strings.Contains(out.String(), CompleteCmdName) || strings.Contains(out.String(), "{{") {
+			t.Errorf("GenCompletion(%q): the script does not call back into the program", shell)
+		}
+	}
+
+	err := p.GenCompletion(&bytes.Buffer{}, "tcsh")
+	if err == nil {
+		t.Error("GenCompletion(\"tcsh\"): expected an error")
+	}
+}
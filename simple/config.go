@@ -0,0 +1,619 @@
+package simple
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultConfigName is the default base name, without extension, of the
+	// configuration files.
+	DefaultConfigName string = "config"
+
+	// ConfigFlag is the long name of the flag that gives the path of an explicit
+	// configuration file. It must come before the command. (i.e., "tool --config
+	// ./dev.json deploy")
+	ConfigFlag string = "config"
+)
+
+// ConfigDecoder decodes the content of a configuration file.
+//
+// Parameters:
+//   - data: The content of the file.
+//
+// Returns:
+//   - map[string]any: The decoded settings; nested maps are flattened into dotted
+//     keys. (i.e., {"remote": {"url": "x"}} gives "remote.url")
+//   - error: An error if the content is malformed.
+type ConfigDecoder func(data []byte) (map[string]any, error)
+
+// DecodeJSON is the ConfigDecoder of JSON files.
+//
+// Parameters:
+//   - data: The content of the file.
+//
+// Returns:
+//   - map[string]any: The decoded settings.
+//   - error: An error if the content is not a JSON object.
+func DecodeJSON(data []byte) (map[string]any, error) {
+	var values map[string]any
+
+	err := json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// ConfigOptions is the options of the configuration files of a program.
+//
+// Settings are layered, each layer overriding the previous ones:
+//  1. the default values of the flags and arguments;
+//  2. the files "<Dir>/<Name><ext>", one per extension of Decoders, in
+//     lexicographic order of extension;
+//  3. the file given with --config, if any;
+//  4. the environment variables; (See Program.AutoEnv)
+//  5. the command line.
+type ConfigOptions struct {
+	// Name is the base name, without extension, of the configuration files. If
+	// empty, DefaultConfigName is used.
+	Name string
+
+	// Dir is the directory of the configuration files. If empty, the directory
+	// named after the program in os.UserConfigDir() is used.
+	Dir string
+
+	// Decoders maps the extensions of the configuration files, with their leading
+	// dot, to their decoder. The ".json" extension is always decoded by DecodeJSON
+	// unless overridden.
+	Decoders map[string]ConfigDecoder
+}
+
+// Fix implements the errors.Fixer interface.
+func (co *ConfigOptions) Fix() error {
+	if co == nil {
+		return nil
+	}
+
+	co.Name = strings.TrimSpace(co.Name)
+	if co.Name == "" {
+		co.Name = DefaultConfigName
+	}
+
+	co.Dir = strings.TrimSpace(co.Dir)
+
+	decoders := make(map[string]ConfigDecoder, len(co.Decoders)+1)
+
+	for ext, decoder := range co.Decoders {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+
+		if !strings.HasPrefix(ext, ".") || len(ext) == 1 {
+			return fmt.Errorf("extension %q must be a dot followed by at least one character", ext)
+		} else if decoder == nil {
+			return fmt.Errorf("decoder of extension %q cannot be nil", ext)
+		}
+
+		decoders[ext] = decoder
+	}
+
+	_, ok := decoders[".json"]
+	if !ok {
+		decoders[".json"] = DecodeJSON
+	}
+
+	co.Decoders = decoders
+
+	return nil
+}
+
+// setting is a single value of the settings.
+type setting struct {
+	// value is the value of the setting.
+	value any
+
+	// source is the source of the value.
+	source ValueSource
+
+	// origin is where the value comes from: the path of the configuration file,
+	// the name of the environment variable or "command line". Empty for defaults.
+	origin string
+}
+
+// Settings is the merged configuration of a run of the program. It tracks, for
+// every key, where its value came from.
+type Settings struct {
+	// values is the map of keys to their settings.
+	values map[string]setting
+}
+
+// Keys returns the keys of the settings.
+//
+// Returns:
+//   - []string: The sorted keys.
+func (s Settings) Keys() []string {
+	return slices.Sorted(maps.Keys(s.values))
+}
+
+// Has checks whether the given key is set.
+//
+// Parameters:
+//   - key: The dotted key. (i.e., "remote.url")
+//
+// Returns:
+//   - bool: True if the key is set, false otherwise.
+func (s Settings) Has(key string) bool {
+	_, ok := s.values[key]
+	return ok
+}
+
+// Value returns the value of the given key.
+//
+// Parameters:
+//   - key: The dotted key.
+//
+// Returns:
+//   - any: The value.
+//   - bool: True if the key is set, false otherwise.
+func (s Settings) Value(key string) (any, bool) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, false
+	}
+
+	return value.value, true
+}
+
+// String returns the value of the given key as a string. Scalar values are
+// formatted.
+//
+// Parameters:
+//   - key: The dotted key.
+//
+// Returns:
+//   - string: The value.
+//   - bool: True if the key is set to a scalar, false otherwise.
+func (s Settings) String(key string) (string, bool) {
+	value, ok := s.values[key]
+	if !ok {
+		return "", false
+	}
+
+	return setting_string(value.value)
+}
+
+// Source returns the source of the value of the given key.
+//
+// Parameters:
+//   - key: The dotted key.
+//
+// Returns:
+//   - ValueSource: The source. SourceDefault if the key is not set.
+func (s Settings) Source(key string) ValueSource {
+	return s.values[key].source
+}
+
+// Origin returns where the value of the given key comes from: the path of the
+// configuration file, the name of the environment variable or "command line".
+//
+// Parameters:
+//   - key: The dotted key.
+//
+// Returns:
+//   - string: The origin. Empty for defaults and keys that are not set.
+func (s Settings) Origin(key string) string {
+	return s.values[key].origin
+}
+
+// Status returns the source of every key.
+//
+// Returns:
+//   - map[string]ValueSource: The map of keys to their source.
+func (s Settings) Status() map[string]ValueSource {
+	status := make(map[string]ValueSource, len(s.values))
+
+	for key, value := range s.values {
+		status[key] = value.source
+	}
+
+	return status
+}
+
+// set sets the value of the given key.
+//
+// Parameters:
+//   - key: The dotted key.
+//   - value: The value.
+//   - source: The source of the value.
+//   - origin: Where the value comes from.
+func (s *Settings) set(key string, value any, source ValueSource, origin string) {
+	if s.values == nil {
+		s.values = make(map[string]setting)
+	}
+
+	s.values[key] = setting{
+		value:  value,
+		source: source,
+		origin: origin,
+	}
+}
+
+// load loads the given configuration file on top of the settings.
+//
+// Parameters:
+//   - path: The path of the file.
+//   - decoder: The decoder of the file.
+//
+// Returns:
+//   - error: An error if the file could not be read or decoded.
+func (s *Settings) load(path string, decoder ConfigDecoder) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	values, err := decoder(data)
+	if err != nil {
+		return fmt.Errorf("config file %q: %w", path, err)
+	}
+
+	s.merge(values, path)
+
+	return nil
+}
+
+// merge flattens the given values into dotted keys on top of the settings. (See
+// flatten_settings)
+//
+// Parameters:
+//   - values: The values.
+//   - path: The path of the configuration file.
+func (s *Settings) merge(values map[string]any, path string) {
+	flatten_settings("", values, func(key string, value any) {
+		s.set(key, value, SourceConfig, path)
+	})
+}
+
+// flatten_settings calls fn for every value of the given nested values with its
+// dotted key; nested objects are walked rather than passed to fn. (i.e.,
+// {"greet": {"count": 3}} and {"greet.count": 3} both give "greet.count")
+//
+// Parameters:
+//   - prefix: The prefix of the keys. Empty for the root.
+//   - values: The nested values.
+//   - fn: The function called for every value.
+func flatten_settings(prefix string, values map[string]any, fn func(key string, value any)) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		nested, ok := value.(map[string]any)
+		if ok {
+			flatten_settings(key, nested, fn)
+		} else {
+			fn(key, value)
+		}
+	}
+}
+
+// lookup_setting returns the value of the given dotted key in the given nested
+// values, as flatten_settings sees it.
+//
+// Parameters:
+//   - values: The nested values.
+//   - key: The dotted key.
+//
+// Returns:
+//   - any: The value.
+//   - bool: True if the key is set, false otherwise.
+func lookup_setting(values map[string]any, key string) (any, bool) {
+	var res any
+	var found bool
+
+	flatten_settings("", values, func(k string, value any) {
+		if !found && k == key {
+			res, found = value, true
+		}
+	})
+
+	return res, found
+}
+
+// delete_setting removes the given dotted key from the given nested values, whether
+// it is written flat, nested or partly both, and removes the objects it leaves
+// empty.
+//
+// Parameters:
+//   - values: The nested values.
+//   - key: The dotted key.
+//
+// Returns:
+//   - bool: True if the key was set, false otherwise.
+func delete_setting(values map[string]any, key string) bool {
+	parts := strings.Split(key, ".")
+
+	var deleted bool
+
+	for i := 1; i <= len(parts); i++ {
+		head := strings.Join(parts[:i], ".")
+
+		nested, ok := values[head].(map[string]any)
+
+		if i == len(parts) {
+			_, set := values[head]
+			if set && !ok {
+				delete(values, head)
+				deleted = true
+			}
+
+			continue
+		}
+
+		if !ok || !delete_setting(nested, strings.Join(parts[i:], ".")) {
+			continue
+		}
+
+		deleted = true
+
+		if len(nested) == 0 {
+			delete(values, head)
+		}
+	}
+
+	return deleted
+}
+
+// insert_setting writes the given dotted key in the given nested values as nested
+// objects. (i.e., "greet.count" is written as {"greet": {"count": value}}) The key
+// is assumed to not be set already. (See delete_setting)
+//
+// Parameters:
+//   - values: The nested values.
+//   - key: The dotted key.
+//   - value: The value.
+//
+// Returns:
+//   - error: An error if a part of the key holds a value that is not an object, or
+//     if the key holds an object.
+func insert_setting(values map[string]any, key string, value any) error {
+	parts := strings.Split(key, ".")
+
+	for i, part := range parts[:len(parts)-1] {
+		child, ok := values[part]
+		if !ok {
+			child = make(map[string]any)
+			values[part] = child
+		}
+
+		nested, ok := child.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%q is not an object", strings.Join(parts[:i+1], "."))
+		}
+
+		values = nested
+	}
+
+	last := parts[len(parts)-1]
+
+	_, ok := values[last].(map[string]any)
+	if ok {
+		return fmt.Errorf("%q is an object", key)
+	}
+
+	values[last] = value
+
+	return nil
+}
+
+// Settings returns the merged configuration of the command being run. Only
+// meaningful inside a CmdRunFn, a hook or a middleware.
+//
+// The keys of flags and arguments are the full name of their command, with dots
+// instead of spaces, followed by their name. (i.e., "remote.add.url") The values of
+// the flags of the command being run are those used by the run, whatever their
+// source.
+//
+// Returns:
+//   - *Settings: The settings. Never returns nil.
+func (p Program) Settings() *Settings {
+	if p.settings == nil {
+		return &Settings{}
+	}
+
+	return p.settings
+}
+
+// usage returns the usage of the program, without its name. (i.e.,
+// "[--config <file>] <cmd> [args...]")
+//
+// Returns:
+//   - string: The usage of the program.
+func (p Program) usage() string {
+	if p.Config == nil {
+		return "<cmd> [args...]"
+	}
+
+	return "[" + LongFlagPrefix + ConfigFlag + " <file>] <cmd> [args...]"
+}
+
+// split_config_flag removes the leading --config flag from the given arguments.
+//
+// Parameters:
+//   - args: The arguments of the program, starting with its name.
+//
+// Returns:
+//   - []string: The arguments without the flag.
+//   - string: The path given to the flag. Empty if there is none.
+//   - error: An *ErrFlagMissingArg if the flag has no value.
+func (p Program) split_config_flag(args []string) ([]string, string, error) {
+	if p.Config == nil || len(args) < 2 {
+		return args, "", nil
+	}
+
+	flag := LongFlagPrefix + ConfigFlag
+
+	arg := args[1]
+
+	if arg == flag {
+		if len(args) < 3 {
+			return nil, "", NewErrFlagMissingArg(false, &Flag{long_name: ConfigFlag})
+		}
+
+		return append([]string{args[0]}, args[3:]...), args[2], nil
+	}
+
+	path, ok := strings.CutPrefix(arg, flag+"=")
+	if !ok {
+		return args, "", nil
+	}
+
+	return append([]string{args[0]}, args[2:]...), path, nil
+}
+
+// load_settings loads the configuration files of the program.
+//
+// Parameters:
+//   - explicit: The path given to --config. Empty if there is none.
+//
+// Returns:
+//   - *Settings: The settings of the configuration files. Never returns nil.
+//   - error: An error if a file could not be read or decoded.
+func (p Program) load_settings(explicit string) (*Settings, error) {
+	settings := &Settings{}
+
+	if p.Config == nil {
+		return settings, nil
+	}
+
+	dir := p.Config.Dir
+
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err == nil {
+			dir = filepath.Join(base, p.Name)
+		}
+	}
+
+	if dir != "" {
+		for _, ext := range slices.Sorted(maps.Keys(p.Config.Decoders)) {
+			err := settings.load(filepath.Join(dir, p.Config.Name+ext), p.Config.Decoders[ext])
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	if explicit == "" {
+		return settings, nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(explicit))
+
+	decoder, ok := p.Config.Decoders[ext]
+	if !ok {
+		return nil, fmt.Errorf("config file %q: no decoder for extension %q", explicit, ext)
+	}
+
+	err := settings.load(explicit, decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// config_key returns the key of the given flag or argument of the given command.
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//   - name: The long name of the flag or the name of the argument.
+//
+// Returns:
+//   - string: The dotted key. (i.e., "remote.add.url")
+func config_key(cmd *Command, name string) string {
+	return strings.ReplaceAll(cmd.FullName(), " ", ".") + "." + name
+}
+
+// apply_config_flags sets the flags of the given command that were set neither on
+// the command line nor from the environment from the settings, and then records the
+// values of all the flags in the settings.
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//
+// Returns:
+//   - error: An *ErrInvalidSetting if a setting holds an invalid value.
+func (p Program) apply_config_flags(cmd *Command) error {
+	if cmd.flag_set == nil {
+		return nil
+	}
+
+	settings := p.Settings()
+
+	for flag := range cmd.flag_set.Flags() {
+		key := config_key(cmd, flag.long_name)
+
+		if flag.source == SourceDefault && settings.Has(key) {
+			value, ok := settings.String(key)
+			if !ok {
+				return NewErrInvalidSetting(key, settings.Origin(key), errors.New("value must be a scalar"))
+			}
+
+			err := flag.value.Set(value)
+			if err != nil {
+				return NewErrInvalidSetting(key, settings.Origin(key), err)
+			}
+
+			flag.source = SourceConfig
+		}
+
+		getter, ok := flag.value.(Getter)
+		if !ok || flag.source == SourceConfig {
+			continue
+		}
+
+		var origin string
+
+		switch flag.source {
+		case SourceEnv:
+			origin = p.env_name(cmd, flag.env, flag.long_name)
+		case SourceCommandLine:
+			origin = "command line"
+		}
+
+		settings.set(key, getter.Get(), flag.source, origin)
+	}
+
+	return nil
+}
+
+// setting_string formats the given scalar value of a setting.
+//
+// Parameters:
+//   - value: The value.
+//
+// Returns:
+//   - string: The formatted value.
+//   - bool: True if the value is a scalar, false otherwise.
+func setting_string(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case bool:
+		return strconv.FormatBool(value), true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case int, int64, uint, uint64, json.Number:
+		return fmt.Sprint(value), true
+	default:
+		return "", false
+	}
+}
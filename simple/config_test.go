@@ -0,0 +1,189 @@
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// new_test_config_program creates the program used by the tests of the
+// configuration files. Its "greet" command prints the value and the source of its
+// --name flag and of its <target> argument.
+func new_test_config_program(dir string, env map[string]string, out *bytes.Buffer) *Program {
+	greet := &Command{
+		Name:     "greet",
+		Argument: OptionalArgs(0, StringArg("target")),
+	}
+
+	name := greet.Flags().String("name", "world", "Name to greet")
+
+	greet.RunFn = func(p *Program, args []string) error {
+		settings := p.Settings()
+
+		_, err := fmt.Fprintf(p, "%s %v %v", *name, settings.Source("greet.name"), args)
+		return err
+	}
+
+	p := &Program{
+		Name:    "tool",
+		Version: "1.0.0",
+		Out:     out,
+		Err:     out,
+		Config:  &ConfigOptions{Dir: dir},
+		AutoEnv: true,
+		LookupEnv: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	p.AddCommands(greet)
+
+	return p
+}
+
+// write_test_file writes the given file in the given directory, or fails the test.
+func write_test_file(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return path
+}
+
+func TestRunConfigLayers(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		explicit string
+		env      map[string]string
+		args     []string
+		want     string
+	}{
+		{
+			name: "default",
+			args: []string{"greet"},
+			want: "world default []",
+		},
+		{
+			name:   "config file",
+			config: `{"greet": {"name": "file", "target": "moon"}}`,
+			args:   []string{"greet"},
+			want:   "file config [moon]",
+		},
+		{
+			name:     "explicit file overrides the directory",
+			config:   `{"greet.name": "file"}`,
+			explicit: `{"greet.name": "explicit"}`,
+			args:     []string{"greet"},
+			want:     "explicit config []",
+		},
+		{
+			name:   "environment overrides the files",
+			config: `{"greet.name": "file"}`,
+			env:    map[string]string{"TOOL_GREET_NAME": "env"},
+			args:   []string{"greet"},
+			want:   "env env []",
+		},
+		{
+			name:   "command line overrides everything",
+			config: `{"greet.name": "file"}`,
+			env:    map[string]string{"TOOL_GREET_NAME": "env", "TOOL_GREET_TARGET": "sun"},
+			args:   []string{"greet", "--name=cli", "mars"},
+			want:   "cli command line [mars]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			if tt.config != "" {
+				write_test_file(t, dir, DefaultConfigName+".json", tt.config)
+			}
+
+			args := []string{"tool"}
+
+			if tt.explicit != "" {
+				args = append(args, "--config", write_test_file(t, t.TempDir(), "explicit.json", tt.explicit))
+			}
+
+			var out bytes.Buffer
+
+			p := new_test_config_program(dir, tt.env, &out)
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run(append(args, tt.args...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunBrokenConfig(t *testing.T) {
+	dir := t.TempDir()
+	write_test_file(t, dir, DefaultConfigName+".json", `{"greet": `)
+
+	tests := []struct {
+		args  []string
+		fails bool
+	}{
+		{[]string{"tool", "greet"}, true},
+		{[]string{"tool", "help"}, false},
+		{[]string{"tool", "help", "greet"}, false},
+		{[]string{"tool", "version"}, false},
+		{[]string{"tool", "completion", "bash"}, false},
+		{[]string{"tool", CompleteCmdName, "gr"}, false},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+
+		p := new_test_config_program(dir, nil, &out)
+
+		err := p.Fix()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = p.Run(tt.args)
+		if tt.fails && err == nil {
+			t.Errorf("Run(%q): expected an error", tt.args)
+		} else if !tt.fails && err != nil {
+			t.Errorf("Run(%q): unexpected error: %v", tt.args, err)
+		}
+	}
+}
+
+func TestRunConfigMissingExplicitFile(t *testing.T) {
+	var out bytes.Buffer
+
+	p := new_test_config_program(t.TempDir(), nil, &out)
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = p.Run([]string{"tool", "--config", filepath.Join(t.TempDir(), "nope.json"), "greet"})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v, want one that wraps os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,203 @@
+package simple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultGracePeriod is the time a command has to return once its context is
+	// cancelled by a signal before the process is forcefully terminated.
+	DefaultGracePeriod time.Duration = 10 * time.Second
+)
+
+// CmdRunContextFn is the context-aware variant of CmdRunFn.
+//
+// Parameters:
+//   - ctx: The context of the run. It is cancelled when the program is interrupted.
+//   - p: The program that runs the command.
+//   - args: The positional arguments of the command, as strings.
+//
+// Returns:
+//   - error: An error if the command failed.
+type CmdRunContextFn func(ctx context.Context, p *Program, args []string) error
+
+// Context returns the context of the command being run. Only meaningful inside a
+// CmdRunFn, a hook or a middleware.
+//
+// Returns:
+//   - context.Context: The context. context.Background() if the program was not
+//     run with RunContext.
+func (p Program) Context() context.Context {
+	if p.ctx == nil {
+		return context.Background()
+	}
+
+	return p.ctx
+}
+
+// RunContext is like Run, but the command runs with the given context, which is
+// cancelled with an *ErrInterrupted cause when the process receives SIGINT or
+// SIGTERM. A second signal, or a command that does not return within the grace
+// period, terminates the process through the exit function of the exit policy.
+// When runs are nested, only the innermost one handles the signals.
+//
+// Parameters:
+//   - ctx: The parent context. If nil, context.Background() is used.
+//   - args: The arguments to run the program with. This is os.Args.
+//
+// Returns:
+//   - error: The error that occurred, joined to an *ErrInterrupted if the program
+//     was interrupted. Nil if the command ran successfully.
+func (p Program) RunContext(ctx context.Context, args []string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	signals := make(chan os.Signal, 2)
+
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	level := claim_signals()
+	defer release_signals()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go p.watch_signals(level, signals, cancel, done)
+
+	p.ctx = ctx
+
+	err := p.Run(args)
+
+	var interrupted *ErrInterrupted
+
+	if errors.As(context.Cause(ctx), &interrupted) && !errors.As(err, &interrupted) {
+		err = errors.Join(err, interrupted)
+	}
+
+	return err
+}
+
+// signal_levels tracks the nested runs that listen to signals, such as the commands
+// run from an interactive session. Only the innermost one handles them.
+var signal_levels struct {
+	sync.Mutex
+
+	// depth is the number of runs that listen to signals.
+	depth int
+}
+
+// claim_signals makes the caller the innermost listener of signals. It must be
+// paired with release_signals.
+//
+// Returns:
+//   - int: The level of the caller.
+func claim_signals() int {
+	signal_levels.Lock()
+	defer signal_levels.Unlock()
+
+	signal_levels.depth++
+
+	return signal_levels.depth
+}
+
+// release_signals gives the signals back to the enclosing listener.
+func release_signals() {
+	signal_levels.Lock()
+	defer signal_levels.Unlock()
+
+	signal_levels.depth--
+}
+
+// owns_signals checks whether the listener of the given level is the innermost one.
+//
+// Parameters:
+//   - level: The level of the listener.
+//
+// Returns:
+//   - bool: True if the listener handles the signals, false otherwise.
+func owns_signals(level int) bool {
+	signal_levels.Lock()
+	defer signal_levels.Unlock()
+
+	return signal_levels.depth == level
+}
+
+// watch_signals cancels the context of the run on the first signal and terminates
+// the process on the second one or when the grace period expires. Signals are
+// ignored while a nested run handles them.
+//
+// Parameters:
+//   - level: The level of the run. (See claim_signals)
+//   - signals: The channel of the signals.
+//   - cancel: The function that cancels the context of the run.
+//   - done: The channel closed once the run is over.
+func (p Program) watch_signals(level int, signals <-chan os.Signal, cancel context.CancelCauseFunc, done <-chan struct{}) {
+	var (
+		interrupted *ErrInterrupted
+		timeout     <-chan time.Time
+	)
+
+	grace := p.GracePeriod
+	if grace == 0 {
+		grace = DefaultGracePeriod
+	}
+
+	for interrupted == nil {
+		select {
+		case <-done:
+			return
+		case sig := <-signals:
+			if owns_signals(level) {
+				interrupted = NewErrInterrupted(sig)
+			}
+		}
+	}
+
+	cancel(interrupted)
+
+	if grace > 0 {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	for forced := false; !forced; {
+		select {
+		case <-done:
+			return
+		case <-signals:
+			if owns_signals(level) {
+				_, _ = fmt.Fprintln(p.error_output(), "Received a second signal; forcing exit")
+				forced = true
+			}
+		case <-timeout:
+			_, _ = fmt.Fprintf(p.error_output(), "Command did not stop within %s; forcing exit\n", grace)
+			forced = true
+		}
+	}
+
+	policy := p.ExitPolicy
+	if policy == nil {
+		policy = DefaultExitPolicy
+	}
+
+	exit_fn := policy.ExitFn
+	if exit_fn == nil {
+		exit_fn = os.Exit
+	}
+
+	exit_fn(interrupted.ExitCode())
+}
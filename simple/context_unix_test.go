@@ -0,0 +1,252 @@
+//go:build unix
+
+package simple
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// new_test_watch_program creates the program used by the tests of watch_signals.
+// Its exit function sends the exit code to the returned channel.
+func new_test_watch_program(grace time.Duration, err_out *watched_buffer) (*Program, <-chan int) {
+	codes := make(chan int, 1)
+
+	p := &Program{
+		Name:        "tool",
+		Err:         err_out,
+		GracePeriod: grace,
+		ExitPolicy:  &ExitPolicy{ExitFn: func(code int) { codes <- code }},
+	}
+
+	return p, codes
+}
+
+// wait_for_cause waits until the given context is cancelled and returns its
+// cause, or fails the test.
+func wait_for_cause(t *testing.T, ctx context.Context) error {
+	t.Helper()
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the context to be cancelled")
+		return nil
+	}
+}
+
+func TestWatchSignals(t *testing.T) {
+	tests := []struct {
+		name    string
+		grace   time.Duration
+		signals []os.Signal
+		code    int
+		message string
+	}{
+		{"second signal", time.Hour, []os.Signal{syscall.SIGINT, syscall.SIGINT}, 130, "Received a second signal"},
+		{"grace period", 10 * time.Millisecond, []os.Signal{syscall.SIGTERM}, 143, "did not stop within 10ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err_out watched_buffer
+
+			p, codes := new_test_watch_program(tt.grace, &err_out)
+
+			level := claim_signals()
+			defer release_signals()
+
+			ctx, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+
+			signals := make(chan os.Signal, len(tt.signals))
+			done := make(chan struct{})
+			defer close(done)
+
+			go p.watch_signals(level, signals, cancel, done)
+
+			signals <- tt.signals[0]
+
+			var interrupted *ErrInterrupted
+
+			cause := wait_for_cause(t, ctx)
+			if !errors.As(cause, &interrupted) || interrupted.Signal != tt.signals[0] {
+				t.Fatalf("cause = %v, want an *ErrInterrupted for %v", cause, tt.signals[0])
+			}
+
+			for _, sig := range tt.signals[1:] {
+				signals <- sig
+			}
+
+			select {
+			case code := <-codes:
+				if code != tt.code {
+					t.Errorf("exit code = %d, want %d", code, tt.code)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for the forced exit")
+			}
+
+			err_out.wait_for(t, tt.message)
+		})
+	}
+}
+
+func TestWatchSignalsReturnsWhenDone(t *testing.T) {
+	var err_out watched_buffer
+
+	p, codes := new_test_watch_program(10*time.Millisecond, &err_out)
+
+	level := claim_signals()
+	defer release_signals()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	go p.watch_signals(level, signals, cancel, done)
+
+	signals <- syscall.SIGINT
+	wait_for_cause(t, ctx)
+
+	// The command returned within the grace period.
+	close(done)
+
+	select {
+	case code := <-codes:
+		t.Errorf("unexpected forced exit with code %d", code)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatchSignalsNested(t *testing.T) {
+	var err_out watched_buffer
+
+	p, _ := new_test_watch_program(time.Hour, &err_out)
+
+	outer := claim_signals()
+	defer release_signals()
+
+	outer_ctx, outer_cancel := context.WithCancelCause(context.Background())
+	defer outer_cancel(nil)
+
+	outer_signals := make(chan os.Signal, 1)
+	outer_done := make(chan struct{})
+	defer close(outer_done)
+
+	go p.watch_signals(outer, outer_signals, outer_cancel, outer_done)
+
+	inner := claim_signals()
+
+	inner_ctx, inner_cancel := context.WithCancelCause(context.Background())
+	defer inner_cancel(nil)
+
+	inner_signals := make(chan os.Signal, 1)
+	inner_done := make(chan struct{})
+
+	go p.watch_signals(inner, inner_signals, inner_cancel, inner_done)
+
+	// Both runs receive the signal; only the innermost one handles it.
+	outer_signals <- syscall.SIGINT
+	inner_signals <- syscall.SIGINT
+
+	wait_for_cause(t, inner_ctx)
+
+	select {
+	case <-outer_ctx.Done():
+		t.Error("the outer run handled a signal of the inner run")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(inner_done)
+	release_signals()
+
+	// Once the inner run is over, the outer one handles the signals again.
+	outer_signals <- syscall.SIGINT
+
+	wait_for_cause(t, outer_ctx)
+}
+
+func TestRunContextSignal(t *testing.T) {
+	var err_out watched_buffer
+
+	cmd := &Command{
+		Name: "wait",
+		RunContextFn: func(ctx context.Context, _ *Program, _ []string) error {
+			err := syscall.Kill(os.Getpid(), syscall.SIGINT)
+			if err != nil {
+				return err
+			}
+
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+	}
+
+	p, _ := new_test_watch_program(time.Hour, &err_out)
+	p.AddCommands(cmd)
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = p.RunContext(context.Background(), []string{"tool", "wait"})
+
+	var interrupted *ErrInterrupted
+
+	if !errors.As(err, &interrupted) || interrupted.Signal != syscall.SIGINT {
+		t.Fatalf("error = %v, want an *ErrInterrupted for SIGINT", err)
+	}
+
+	code := p.ExitCode(err)
+	if code != 130 {
+		t.Errorf("exit code = %d, want 130", code)
+	}
+}
+
+func TestRunContextParentCancelled(t *testing.T) {
+	var err_out watched_buffer
+
+	parent, cancel := context.WithCancel(context.Background())
+
+	cmd := &Command{
+		Name: "wait",
+		RunContextFn: func(ctx context.Context, _ *Program, _ []string) error {
+			cancel()
+
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+	}
+
+	p, _ := new_test_watch_program(time.Hour, &err_out)
+	p.AddCommands(cmd)
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = p.RunContext(parent, []string{"tool", "wait"})
+
+	var interrupted *ErrInterrupted
+
+	if !errors.Is(err, context.Canceled) || errors.As(err, &interrupted) {
+		t.Errorf("error = %v, want context.Canceled without an *ErrInterrupted", err)
+	}
+
+	code := p.ExitCode(err)
+	if code != ExitFailure {
+		t.Errorf("exit code = %d, want %d", code, ExitFailure)
+	}
+}
@@ -0,0 +1,143 @@
+package simple
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DeprecatedMark is the mark that precedes the brief of deprecated commands in
+	// the command listings of the help.
+	DeprecatedMark string = "[deprecated]"
+)
+
+// Deprecation is the deprecation metadata of a command.
+type Deprecation struct {
+	// Message explains why the command is deprecated. Leave empty if not needed.
+	Message string
+
+	// Replacement is the full name of the command to use instead. (i.e.,
+	// "remote add") Leave empty if there is none.
+	Replacement string
+
+	// RemovedIn is the version of the program in which the command will be removed.
+	// Leave empty if not planned yet.
+	RemovedIn string
+}
+
+// Fix implements the errors.Fixer interface.
+func (d *Deprecation) Fix() error {
+	if d == nil {
+		return nil
+	}
+
+	d.Message = strings.TrimSpace(d.Message)
+	d.Replacement = strings.TrimSpace(d.Replacement)
+	d.RemovedIn = strings.TrimSpace(d.RemovedIn)
+
+	if d.RemovedIn != "" {
+		_, err := parse_version(d.RemovedIn)
+		if err != nil {
+			return fmt.Errorf("invalid removal version %q: %w", d.RemovedIn, err)
+		}
+	}
+
+	return nil
+}
+
+// Warning returns the warning printed when the given deprecated command is run.
+//
+// Parameters:
+//   - command: The full name of the deprecated command.
+//
+// Returns:
+//   - string: The warning. (i.e., `command "rm" is deprecated and will be removed
+//     in 2.0; use "remove" instead`)
+func (d Deprecation) Warning(command string) string {
+	var builder strings.Builder
+
+	builder.WriteString("command ")
+	builder.WriteString(strconv.Quote(command))
+	builder.WriteString(" is deprecated")
+
+	if d.RemovedIn != "" {
+		builder.WriteString(" and will be removed in ")
+		builder.WriteString(d.RemovedIn)
+	}
+
+	if d.Replacement != "" {
+		builder.WriteString("; use ")
+		builder.WriteString(strconv.Quote(d.Replacement))
+		builder.WriteString(" instead")
+	}
+
+	if d.Message != "" {
+		builder.WriteString(": ")
+		builder.WriteString(d.Message)
+	}
+
+	return builder.String()
+}
+
+// check_removals checks that no command of the given list, or any of their
+// sub-commands, is due for removal in the given version of the program. Nothing is
+// checked if no command has a removal version; if the version of the program is
+// malformed, a warning is written instead.
+//
+// Parameters:
+//   - version: The version of the program. Nothing is checked if empty.
+//   - commands: The commands to check.
+//   - w: The writer of the warning.
+//
+// Returns:
+//   - error: An error if a command is due for removal.
+func check_removals(version string, commands []*Command, w io.Writer) error {
+	scheduled := scheduled_removals(commands)
+
+	if version == "" || len(scheduled) == 0 {
+		return nil
+	}
+
+	_, err := parse_version(version)
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "Warning: the removal of deprecated commands is not checked; invalid version %q: %v\n", version, err)
+		return nil
+	}
+
+	for _, cmd := range scheduled {
+		res, err := CompareVersions(version, cmd.Deprecated.RemovedIn)
+		if err != nil {
+			return err
+		}
+
+		if res >= 0 {
+			return fmt.Errorf("command %q was due for removal in version %s; the program is at version %s", cmd.FullName(), cmd.Deprecated.RemovedIn, version)
+		}
+	}
+
+	return nil
+}
+
+// scheduled_removals returns the commands of the given list, and of their
+// sub-commands, that have a removal version.
+//
+// Parameters:
+//   - commands: The commands.
+//
+// Returns:
+//   - []*Command: The commands with a removal version, depth-first.
+func scheduled_removals(commands []*Command) []*Command {
+	var scheduled []*Command
+
+	for _, cmd := range commands {
+		if cmd.Deprecated != nil && cmd.Deprecated.RemovedIn != "" {
+			scheduled = append(scheduled, cmd)
+		}
+
+		scheduled = append(scheduled, scheduled_removals(ordered_commands(cmd.sub_commands, cmd.sub_order))...)
+	}
+
+	return scheduled
+}
@@ -0,0 +1,90 @@
+package simple
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2", "1.2.0", 0},
+		{"1.10", "1.9", 1},
+		{"1.2-rc.1", "1.2.0", -1},
+		{"1.2-rc.2", "1.2-rc.10", -1},
+		{"1.2-alpha", "1.2-1", 1},
+		{"2.0.0+build.5", "2.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := CompareVersions(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("CompareVersions(%q, %q): unexpected error: %v", tt.a, tt.b, err)
+		} else if got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	_, err := CompareVersions("dev", "1.0")
+	if err == nil {
+		t.Error("CompareVersions(\"dev\", \"1.0\"): expected an error")
+	}
+}
+
+func TestProgramFixRemovals(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		removed []string
+		fails   string
+		warns   bool
+	}{
+		{"not due", "1.0.0", []string{"2.0.0"}, "", false},
+		{"due", "2.0.0", []string{"2.0.0"}, `command "old" was due for removal in version 2.0.0`, false},
+		{"past due", "2.1", []string{"2.0.0"}, `command "old" was due for removal`, false},
+		{"due sub-command", "2.0.0", []string{"3.0.0", "1.5"}, `command "remote old" was due for removal in version 1.5`, false},
+		{"no removal version", "dev", []string{""}, "", false},
+		{"no deprecated command", "dev", nil, "", false},
+		{"no program version", "", []string{"1.0.0"}, "", false},
+		{"malformed version", "dev", []string{"2.0.0"}, "", true},
+		{"malformed version with a due command", "nightly", []string{"0.1", "0.2"}, "", true},
+		{"malformed removal version", "2.0.0", []string{"soon"}, "soon", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err_out bytes.Buffer
+
+			p := &Program{Name: "tool", Version: tt.version, Err: &err_out}
+			p.AddCommands(&Command{Name: "status"})
+
+			for i, removed := range tt.removed {
+				cmd := &Command{Name: "old", Deprecated: &Deprecation{RemovedIn: removed}}
+
+				if i == 0 {
+					p.AddCommands(cmd)
+				} else {
+					remote := &Command{Name: "remote"}
+					remote.AddCommands(cmd)
+					p.AddCommands(remote)
+				}
+			}
+
+			err := p.Fix()
+			if tt.fails == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.fails != "" && (err == nil || !strings.Contains(err.Error(), tt.fails)) {
+				t.Errorf("error = %v, want one that contains %q", err, tt.fails)
+			}
+
+			warned := strings.Contains(err_out.String(), "Warning:")
+			if warned != tt.warns {
+				t.Errorf("warning = %q, want a warning: %v", err_out.String(), tt.warns)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package simple
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// EnvNone is the environment variable of the arguments and flags that must not
+	// fall back to any environment variable, not even the automatic one.
+	EnvNone string = "-"
+)
+
+// EnvName builds the name of an environment variable from the given parts: they are
+// joined with underscores, upper-cased, and every character that is neither a
+// letter nor a digit is replaced by an underscore. (i.e., "tool", "remote add",
+// "dry-run" gives "TOOL_REMOTE_ADD_DRY_RUN")
+//
+// Parameters:
+//   - parts: The parts of the name.
+//
+// Returns:
+//   - string: The name of the environment variable.
+func EnvName(parts ...string) string {
+	name := strings.Join(parts, "_")
+
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, name)
+}
+
+// env_name returns the environment variable that the given argument or flag of the
+// given command falls back to.
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//   - declared: The environment variable declared by the argument or the flag.
+//   - name: The name of the argument or the long name of the flag.
+//
+// Returns:
+//   - string: The environment variable. Empty if there is none.
+func (p Program) env_name(cmd *Command, declared, name string) string {
+	switch declared {
+	case EnvNone:
+		return ""
+	case "":
+		if !p.AutoEnv {
+			return ""
+		}
+
+		return EnvName(p.Name, cmd.FullName(), name)
+	default:
+		return declared
+	}
+}
+
+// lookup_fallback looks up the given environment variable. Empty variables count as
+// not set.
+//
+// Parameters:
+//   - name: The environment variable. Empty if there is none.
+//
+// Returns:
+//   - string: The value of the variable.
+//   - bool: True if the variable is set and not empty, false otherwise.
+func (p Program) lookup_fallback(name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+
+	value, ok := p.lookup_env(name)
+	if !ok || value == "" {
+		return "", false
+	}
+
+	return value, true
+}
+
+// apply_env_flags sets the flags of the given command that were not set on the
+// command line from their environment variable.
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//
+// Returns:
+//   - error: An *ErrInvalidEnv if a variable holds an invalid value.
+func (p Program) apply_env_flags(cmd *Command) error {
+	if cmd.flag_set == nil {
+		return nil
+	}
+
+	for flag := range cmd.flag_set.Flags() {
+		if flag.source != SourceDefault {
+			continue
+		}
+
+		name := p.env_name(cmd, flag.env, flag.long_name)
+
+		value, ok := p.lookup_fallback(name)
+		if !ok {
+			continue
+		}
+
+		err := flag.value.Set(value)
+		if err != nil {
+			return NewErrInvalidEnv(name, value, err)
+		}
+
+		flag.source = SourceEnv
+	}
+
+	return nil
+}
+
+// fallback_args appends to the given positional arguments the fallbacks of the
+// positionals that follow them, stopping at the first one that has none. The
+// fallback of a positional is its environment variable or, if not set, its setting
+// in the configuration files. (See positional_fallback)
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//   - args: The positional arguments given on the command line.
+//
+// Returns:
+//   - []string: The positional arguments, followed by their fallbacks. Nil if an
+//     error occurred.
+//   - error: An *ErrInvalidEnv or an *ErrInvalidSetting if a fallback holds an
+//     invalid value.
+func (p Program) fallback_args(cmd *Command, args []string) ([]string, error) {
+	arg := cmd.Argument
+
+	for i := len(args); i < len(arg.positionals); i++ {
+		// The last positional takes every argument that follows it.
+		limit := 1
+
+		if i == len(arg.positionals)-1 {
+			limit = arg.max
+
+			if limit != -1 {
+				limit -= i
+			}
+		}
+
+		values, err := p.positional_fallback(cmd, arg.positionals[i], limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(values) == 0 {
+			break
+		}
+
+		args = append(args, values...)
+	}
+
+	return args, nil
+}
+
+// positional_fallback returns the fallback of the given positional argument; that is,
+// its environment variable or, if not set, its setting in the configuration files.
+// When the positional may take more than one argument, the fallback is split into
+// words as SplitLine does, so that "a b" gives the arguments "a" and "b".
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//   - pos: The positional argument. Assumed to not be nil.
+//   - limit: The maximum number of arguments the positional takes. -1 means no
+//     maximum.
+//
+// Returns:
+//   - []string: The arguments of the fallback. Nil if the positional has none.
+//   - error: An *ErrInvalidEnv or an *ErrInvalidSetting if the fallback is not a
+//     valid value of the positional.
+func (p Program) positional_fallback(cmd *Command, pos *Positional, limit int) ([]string, error) {
+	name := p.env_name(cmd, pos.Env, pos.Name)
+
+	value, ok := p.lookup_fallback(name)
+
+	invalid := func(reason error) error {
+		return NewErrInvalidEnv(name, value, reason)
+	}
+
+	if !ok {
+		key := config_key(cmd, pos.Name)
+		settings := p.Settings()
+
+		value, ok = settings.String(key)
+		if !ok {
+			return nil, nil
+		}
+
+		invalid = func(reason error) error {
+			return NewErrInvalidSetting(key, settings.Origin(key), reason)
+		}
+	}
+
+	values := []string{value}
+
+	if limit != 1 {
+		var err error
+
+		values, err = SplitLine(value)
+		if err != nil {
+			return nil, invalid(err)
+		}
+
+		if limit != -1 && len(values) > limit {
+			return nil, invalid(fmt.Errorf("expected at most %d values, got %d", limit, len(values)))
+		}
+	}
+
+	fn := pos.ParseFn
+	if fn == nil {
+		fn = parse_string
+	}
+
+	for _, value := range values {
+		_, err := fn(value)
+		if err != nil {
+			return nil, invalid(err)
+		}
+	}
+
+	return values, nil
+}
+
+// env_rows returns the rows of the environment listing of the given command; one
+// row per argument or flag that falls back to an environment variable, made of the
+// variable and of the argument or flag.
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//
+// Returns:
+//   - [][]string: The rows of the listing.
+func (p Program) env_rows(cmd *Command) [][]string {
+	var rows [][]string
+
+	if cmd.Argument != nil {
+		for _, pos := range cmd.Argument.positionals {
+			name := p.env_name(cmd, pos.Env, pos.Name)
+			if name != "" {
+				rows = append(rows, []string{name, write_arg(pos.Name)})
+			}
+		}
+	}
+
+	if cmd.flag_set != nil {
+		for flag := range cmd.flag_set.Flags() {
+			name := p.env_name(cmd, flag.env, flag.long_name)
+			if name != "" {
+				rows = append(rows, []string{name, LongFlagPrefix + flag.long_name})
+			}
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,241 @@
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"tool", "remote add", "dry-run"}, "TOOL_REMOTE_ADD_DRY_RUN"},
+		{[]string{"my.tool", "v2"}, "MY_TOOL_V2"},
+		{[]string{"tööl"}, "T__L"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		got := EnvName(tt.parts...)
+		if got != tt.want {
+			t.Errorf("EnvName(%q) = %q, want %q", tt.parts, got, tt.want)
+		}
+	}
+}
+
+// new_test_env_program creates the program used by the tests of the environment
+// variables. Its "deploy" command prints its flags and arguments.
+func new_test_env_program(auto_env bool, env map[string]string, out *bytes.Buffer) *Program {
+	region := StringArg("region")
+	region.Env = "REGION"
+
+	deploy := &Command{
+		Name:     "deploy",
+		Argument: OptionalArgs(0, StringArg("target"), region),
+	}
+
+	count := deploy.Flags().Int("count", 1, "Number of replicas")
+	token := deploy.Flags().String("token", "", "Access token", WithEnv("TOKEN"))
+	debug := deploy.Flags().Bool("debug", false, "Debug output", WithEnv(EnvNone))
+
+	deploy.RunFn = func(p *Program, args []string) error {
+		_, err := fmt.Fprintf(p, "%d %q %v %v", *count, *token, *debug, args)
+		return err
+	}
+
+	p := &Program{
+		Name:    "tool",
+		Out:     out,
+		Err:     out,
+		AutoEnv: auto_env,
+		LookupEnv: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	p.AddCommands(deploy)
+
+	return p
+}
+
+func TestRunEnvFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		auto_env bool
+		env      map[string]string
+		args     []string
+		want     string
+	}{
+		{
+			name: "defaults",
+			args: []string{"deploy"},
+			want: `1 "" false []`,
+		},
+		{
+			name:     "automatic names",
+			auto_env: true,
+			env:      map[string]string{"TOOL_DEPLOY_COUNT": "3", "TOOL_DEPLOY_TARGET": "prod"},
+			args:     []string{"deploy"},
+			want:     `3 "" false [prod]`,
+		},
+		{
+			name: "automatic names are off",
+			env:  map[string]string{"TOOL_DEPLOY_COUNT": "3", "TOOL_DEPLOY_TARGET": "prod"},
+			args: []string{"deploy"},
+			want: `1 "" false []`,
+		},
+		{
+			name: "declared names",
+			env:  map[string]string{"TOKEN": "secret"},
+			args: []string{"deploy"},
+			want: `1 "secret" false []`,
+		},
+		{
+			name:     "declared positional",
+			auto_env: true,
+			env:      map[string]string{"TOOL_DEPLOY_TARGET": "prod", "REGION": "eu"},
+			args:     []string{"deploy"},
+			want:     `1 "" false [prod eu]`,
+		},
+		{
+			name:     "positionals stop at the first gap",
+			auto_env: true,
+			env:      map[string]string{"REGION": "eu"},
+			args:     []string{"deploy"},
+			want:     `1 "" false []`,
+		},
+		{
+			name:     "opted out",
+			auto_env: true,
+			env:      map[string]string{"TOOL_DEPLOY_DEBUG": "true"},
+			args:     []string{"deploy"},
+			want:     `1 "" false []`,
+		},
+		{
+			name:     "empty variables are not set",
+			auto_env: true,
+			env:      map[string]string{"TOOL_DEPLOY_COUNT": ""},
+			args:     []string{"deploy"},
+			want:     `1 "" false []`,
+		},
+		{
+			name:     "command line wins",
+			auto_env: true,
+			env:      map[string]string{"TOOL_DEPLOY_COUNT": "3", "TOKEN": "secret", "TOOL_DEPLOY_TARGET": "prod"},
+			args:     []string{"deploy", "--count=5", "--token", "cli", "staging"},
+			want:     `5 "cli" false [staging]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			p := new_test_env_program(tt.auto_env, tt.env, &out)
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run(append([]string{"tool"}, tt.args...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunInvalidEnv(t *testing.T) {
+	var out bytes.Buffer
+
+	p := new_test_env_program(true, map[string]string{"TOOL_DEPLOY_COUNT": "many"}, &out)
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = p.Run([]string{"tool", "deploy"})
+
+	var invalid *ErrInvalidEnv
+
+	if !errors.As(err, &invalid) {
+		t.Errorf("error = %v, want an *ErrInvalidEnv", err)
+	}
+}
+
+func TestRunEnvRepeatedPositional(t *testing.T) {
+	tests := []struct {
+		name     string
+		argument *Argument
+		value    string
+		want     string
+		err      any
+	}{
+		{"at least", AtLeastNArgs(IntArg("n"), 2), "1 2 3", "[1 2 3]", nil},
+		{"between", BetweenArgs(IntArg("n"), 2, 3), "1 '2'", "[1 2]", nil},
+		{"too few", AtLeastNArgs(IntArg("n"), 2), "1", "", new(*ErrFewArguments)},
+		{"too many", BetweenArgs(IntArg("n"), 2, 3), "1 2 3 4", "", new(*ErrInvalidEnv)},
+		{"invalid value", AtLeastNArgs(IntArg("n"), 2), "1 two", "", new(*ErrInvalidEnv)},
+		{"unclosed quote", AtLeastNArgs(IntArg("n"), 2), "1 '2", "", new(*ErrInvalidEnv)},
+		{"single positional", NewArgument(IntArg("n")), "1 2", "", new(*ErrInvalidEnv)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			p := &Program{
+				Name:     "tool",
+				Out:      &out,
+				Err:      &out,
+				AutoEnv:  true,
+				NoPrompt: true,
+				LookupEnv: func(key string) (string, bool) {
+					return tt.value, key == "TOOL_SUM_N"
+				},
+			}
+
+			p.AddCommands(&Command{
+				Name:     "sum",
+				Argument: tt.argument,
+				RunFn: func(p *Program, args []string) error {
+					_, err := fmt.Fprint(p, args)
+					return err
+				},
+			})
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run([]string{"tool", "sum"})
+
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if out.String() != tt.want {
+					t.Errorf("output = %q, want %q", out.String(), tt.want)
+				}
+
+				return
+			}
+
+			if !errors.As(err, tt.err) {
+				t.Errorf("error = %v, want a %T", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,772 @@
+package simple
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrFlagNotFound is an error that is returned when a flag is not found.
+type ErrFlagNotFound struct {
+	// IsShort is true if the flag is a short flag. False otherwise.
+	IsShort bool
+
+	// Name is the name of the flag.
+	Name string
+}
+
+// Error implements the error interface.
+//
+// Message: "flag {{ .Name }} not found"
+func (e *ErrFlagNotFound) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("flag ")
+
+	if e.IsShort {
+		builder.WriteString(ShortFlagPrefix)
+	} else {
+		builder.WriteString(LongFlagPrefix)
+	}
+
+	builder.WriteString(e.Name)
+
+	builder.WriteString(" not found")
+
+	return builder.String()
+}
+
+// NewErrFlagNotFound creates a new ErrFlagNotFound.
+//
+// Parameters:
+//   - is_short: True if the flag is a short flag. False otherwise.
+//   - name: The name of the flag.
+//
+// Returns:
+//   - *ErrFlagNotFound: The new error. Never returns nil.
+func NewErrFlagNotFound(is_short bool, name string) *ErrFlagNotFound {
+	return &ErrFlagNotFound{
+		IsShort: is_short,
+		Name:    name,
+	}
+}
+
+// ErrFlagMissingArg is an error that is returned when a flag is missing an
+// argument.
+type ErrFlagMissingArg struct {
+	// IsShort is true if the flag is a short flag. False otherwise.
+	IsShort bool
+
+	// Flag is the flag that is missing an argument.
+	Flag *Flag
+}
+
+// Error implements the error interface.
+//
+// Message: "flag {{ .Flag.Name }} requires an argument"
+func (e *ErrFlagMissingArg) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("flag ")
+	builder.WriteString(flag_name(e.IsShort, e.Flag))
+	builder.WriteString(" requires an argument")
+
+	return builder.String()
+}
+
+// NewErrFlagMissingArg creates a new ErrFlagMissingArg.
+//
+// Parameters:
+//   - is_short: True if the flag is a short flag. False otherwise.
+//   - flag: The flag that is missing an argument.
+//
+// Returns:
+//   - *ErrFlagMissingArg: The new error. Never returns nil.
+func NewErrFlagMissingArg(is_short bool, flag *Flag) *ErrFlagMissingArg {
+	return &ErrFlagMissingArg{
+		IsShort: is_short,
+		Flag:    flag,
+	}
+}
+
+// ErrInvalidFlag is an error that is returned when an invalid flag is
+// passed.
+type ErrInvalidFlag struct {
+	// IsShort is true if the flag is a short flag. False otherwise.
+	IsShort bool
+
+	// Flag is the flag that is invalid.
+	Flag *Flag
+
+	// Reason is the reason for the invalid flag.
+	Reason error
+}
+
+// Error implements the error interface.
+//
+// Message: "flag {{ .Flag.Name }} is invalid: {{ .Reason }}"
+func (e *ErrInvalidFlag) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("flag ")
+	builder.WriteString(flag_name(e.IsShort, e.Flag))
+	builder.WriteString(" is invalid")
+
+	if e.Reason != nil {
+		builder.WriteString(": ")
+		builder.WriteString(e.Reason.Error())
+	}
+
+	return builder.String()
+}
+
+// Unwrap is a method that returns the wrapped error.
+//
+// Returns:
+//   - error: The wrapped error.
+func (e *ErrInvalidFlag) Unwrap() error {
+	return e.Reason
+}
+
+// ChangeReason is a method that changes the wrapped error.
+//
+// Parameters:
+//   - reason: The new wrapped error.
+func (e *ErrInvalidFlag) ChangeReason(reason error) {
+	e.Reason = reason
+}
+
+// NewErrInvalidFlag creates a new ErrInvalidFlag.
+//
+// Parameters:
+//   - is_short: True if the flag is a short flag. False otherwise.
+//   - flag: The flag that is invalid.
+//   - reason: The reason for the invalid flag.
+//
+// Returns:
+//   - *ErrInvalidFlag: The new error. Never returns nil.
+func NewErrInvalidFlag(is_short bool, flag *Flag, reason error) *ErrInvalidFlag {
+	return &ErrInvalidFlag{
+		IsShort: is_short,
+		Flag:    flag,
+		Reason:  reason,
+	}
+}
+
+// ErrInvalidArgument is an error that is returned when a positional argument
+// cannot be parsed.
+type ErrInvalidArgument struct {
+	// Name is the name of the argument.
+	Name string
+
+	// Position is the 1-based position of the argument.
+	Position int
+
+	// Value is the value that was passed on the command line.
+	Value string
+
+	// Reason is the reason for the invalid argument.
+	Reason error
+}
+
+// Error implements the error interface.
+//
+// Message: "argument <{{ .Name }}> at position {{ .Position }} is invalid: {{ .Reason }}"
+func (e *ErrInvalidArgument) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("argument <")
+	builder.WriteString(e.Name)
+	builder.WriteString("> at position ")
+	builder.WriteString(strconv.Itoa(e.Position))
+	builder.WriteString(" is invalid")
+
+	if e.Reason != nil {
+		builder.WriteString(": ")
+		builder.WriteString(e.Reason.Error())
+	}
+
+	return builder.String()
+}
+
+// Unwrap is a method that returns the wrapped error.
+//
+// Returns:
+//   - error: The wrapped error.
+func (e *ErrInvalidArgument) Unwrap() error {
+	return e.Reason
+}
+
+// ChangeReason is a method that changes the wrapped error.
+//
+// Parameters:
+//   - reason: The new wrapped error.
+func (e *ErrInvalidArgument) ChangeReason(reason error) {
+	e.Reason = reason
+}
+
+// NewErrInvalidArgument creates a new ErrInvalidArgument.
+//
+// Parameters:
+//   - name: The name of the argument.
+//   - position: The 1-based position of the argument.
+//   - value: The value that was passed on the command line.
+//   - reason: The reason for the invalid argument.
+//
+// Returns:
+//   - *ErrInvalidArgument: The new error. Never returns nil.
+func NewErrInvalidArgument(name string, position int, value string, reason error) *ErrInvalidArgument {
+	return &ErrInvalidArgument{
+		Name:     name,
+		Position: position,
+		Value:    value,
+		Reason:   reason,
+	}
+}
+
+// flag_name is a helper function that returns the name of the flag as it was
+// written on the command line.
+//
+// Parameters:
+//   - is_short: True if the flag is a short flag. False otherwise.
+//   - flag: The flag.
+//
+// Returns:
+//   - string: The name of the flag, including its prefix.
+func flag_name(is_short bool, flag *Flag) string {
+	if flag == nil {
+		return "[no flag specified]"
+	}
+
+	if is_short && flag.short_name != 0 {
+		return ShortFlagPrefix + string(flag.short_name)
+	}
+
+	return LongFlagPrefix + flag.long_name
+}
+
+// ErrFlagConflict is an error for when a flag conflicts with another flag.
+type ErrFlagConflict struct {
+	// Flag is the long name of the flag that conflicts with another flag.
+	Flag string
+
+	// Conflicting is the long name of the flag that conflicts with the flag.
+	Conflicting string
+
+	// Reason is the reason for the conflict.
+	Reason error
+}
+
+// Error implements the error interface.
+//
+// Message:
+//   - "flag --<Flag> conflicts with flag --<Conflicting>: <Reason>"
+//   - "flag --<Flag> conflicts with flag --<Conflicting>" if Reason is nil.
+func (e *ErrFlagConflict) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("flag ")
+	builder.WriteString(LongFlagPrefix)
+	builder.WriteString(e.Flag)
+	builder.WriteString(" conflicts with flag ")
+	builder.WriteString(LongFlagPrefix)
+	builder.WriteString(e.Conflicting)
+
+	if e.Reason != nil {
+		builder.WriteString(": ")
+		builder.WriteString(e.Reason.Error())
+	}
+
+	return builder.String()
+}
+
+// Unwrap is a method that returns the wrapped error.
+//
+// Returns:
+//   - error: The wrapped error.
+func (e *ErrFlagConflict) Unwrap() error {
+	return e.Reason
+}
+
+// ChangeReason is a method that changes the wrapped error.
+//
+// Parameters:
+//   - reason: The new wrapped error.
+func (e *ErrFlagConflict) ChangeReason(reason error) {
+	e.Reason = reason
+}
+
+// NewErrFlagConflict creates a new ErrFlagConflict.
+//
+// Parameters:
+//   - flag: The long name of the flag that conflicts with another flag.
+//   - conflicting: The long name of the flag that conflicts with the flag.
+//   - reason: The reason for the conflict.
+//
+// Returns:
+//   - *ErrFlagConflict: The new error. Never returns nil.
+func NewErrFlagConflict(flag, conflicting string, reason error) *ErrFlagConflict {
+	return &ErrFlagConflict{
+		Flag:        flag,
+		Conflicting: conflicting,
+		Reason:      reason,
+	}
+}
+
+// ErrFewArguments is an error that is returned when too few arguments are passed.
+type ErrFewArguments struct {
+	// Expected is the minimum number of arguments that were expected.
+	Expected int
+
+	// Got is the number of arguments that were passed.
+	Got int
+}
+
+// Error implements the error interface.
+//
+// Message: "expected at least {{ .Expected }} arguments, got {{ .Got }} instead"
+func (e *ErrFewArguments) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("expected at least ")
+	builder.WriteString(strconv.Itoa(e.Expected))
+	builder.WriteString(" arguments, got ")
+	builder.WriteString(strconv.Itoa(e.Got))
+	builder.WriteString(" instead")
+
+	return builder.String()
+}
+
+// NewErrFewArguments creates a new ErrFewArguments.
+//
+// Parameters:
+//   - expected: The minimum number of arguments that were expected.
+//   - got: The number of arguments that were passed.
+//
+// Returns:
+//   - *ErrFewArguments: The new error. Never returns nil.
+func NewErrFewArguments(expected, got int) *ErrFewArguments {
+	return &ErrFewArguments{
+		Expected: expected,
+		Got:      got,
+	}
+}
+
+// ErrManyArguments is an error that is returned when too many arguments are passed.
+type ErrManyArguments struct {
+	// Expected is the maximum number of arguments that were expected.
+	Expected int
+
+	// Got is the number of arguments that were passed.
+	Got int
+}
+
+// Error implements the error interface.
+//
+// Message: "expected at most {{ .Expected }} arguments, got {{ .Got }} instead"
+func (e *ErrManyArguments) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("expected at most ")
+	builder.WriteString(strconv.Itoa(e.Expected))
+	builder.WriteString(" arguments, got ")
+	builder.WriteString(strconv.Itoa(e.Got))
+	builder.WriteString(" instead")
+
+	return builder.String()
+}
+
+// NewErrManyArguments creates a new ErrManyArguments.
+//
+// Parameters:
+//   - expected: The maximum number of arguments that were expected.
+//   - got: The number of arguments that were passed.
+//
+// Returns:
+//   - *ErrManyArguments: The new error. Never returns nil.
+func NewErrManyArguments(expected, got int) *ErrManyArguments {
+	return &ErrManyArguments{
+		Expected: expected,
+		Got:      got,
+	}
+}
+
+// ErrNoCommand is an error that is returned when no command is provided.
+type ErrNoCommand struct {
+	// Command is the full name of the command whose sub-command is missing. Empty
+	// if no command was provided to the program at all.
+	Command string
+}
+
+// Error implements the error interface.
+//
+// Message:
+//   - "no command provided"
+//   - "no sub-command provided for {{ .Command }}" if Command is not empty.
+func (e *ErrNoCommand) Error() string {
+	if e.Command == "" {
+		return "no command provided"
+	}
+
+	return "no sub-command provided for " + strconv.Quote(e.Command)
+}
+
+// NewErrNoCommand creates a new ErrNoCommand.
+//
+// Parameters:
+//   - command: The full name of the command whose sub-command is missing. Empty
+//     if no command was provided to the program at all.
+//
+// Returns:
+//   - *ErrNoCommand: The new error. Never returns nil.
+func NewErrNoCommand(command string) *ErrNoCommand {
+	return &ErrNoCommand{
+		Command: command,
+	}
+}
+
+// ErrUnknownCommand is an error that is returned when an unknown command is provided.
+type ErrUnknownCommand struct {
+	// Command is the full name of the unknown command. (i.e., "remote foo")
+	Command string
+
+	// Suggestions is the list of known commands that are similar to the unknown
+	// one, closest first.
+	Suggestions []string
+}
+
+// Error implements the error interface.
+//
+// Message: "command {{ .Command }} not found"
+func (e *ErrUnknownCommand) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("command ")
+	builder.WriteString(strconv.Quote(e.Command))
+	builder.WriteString(" not found")
+
+	return builder.String()
+}
+
+// NewErrUnknownCommand creates a new ErrUnknownCommand.
+//
+// Parameters:
+//   - command: The full name of the unknown command.
+//   - suggestions: The known commands that are similar to the unknown one.
+//
+// Returns:
+//   - *ErrUnknownCommand: The new error. Never returns nil.
+func NewErrUnknownCommand(command string, suggestions []string) *ErrUnknownCommand {
+	return &ErrUnknownCommand{
+		Command:     command,
+		Suggestions: suggestions,
+	}
+}
+
+// ErrAmbiguousCommand is an error that is returned when a prefix matches more than
+// one command.
+type ErrAmbiguousCommand struct {
+	// Command is the ambiguous prefix.
+	Command string
+
+	// Candidates is the sorted list of the names of the commands that match the
+	// prefix.
+	Candidates []string
+}
+
+// Error implements the error interface.
+//
+// Message: "command {{ .Command }} is ambiguous; it could be {{ .Candidates }}"
+func (e *ErrAmbiguousCommand) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("command ")
+	builder.WriteString(strconv.Quote(e.Command))
+	builder.WriteString(" is ambiguous")
+
+	if len(e.Candidates) > 0 {
+		quoted := make([]string, 0, len(e.Candidates))
+
+		for _, candidate := range e.Candidates {
+			quoted = append(quoted, strconv.Quote(candidate))
+		}
+
+		builder.WriteString("; it could be ")
+		builder.WriteString(strings.Join(quoted, ", "))
+	}
+
+	return builder.String()
+}
+
+// NewErrAmbiguousCommand creates a new ErrAmbiguousCommand.
+//
+// Parameters:
+//   - command: The ambiguous prefix.
+//   - candidates: The names of the commands that match the prefix.
+//
+// Returns:
+//   - *ErrAmbiguousCommand: The new error. Never returns nil.
+func NewErrAmbiguousCommand(command string, candidates []string) *ErrAmbiguousCommand {
+	return &ErrAmbiguousCommand{
+		Command:    command,
+		Candidates: candidates,
+	}
+}
+
+// ErrInterrupted is an error that is returned when the program is interrupted by a
+// signal.
+type ErrInterrupted struct {
+	// Signal is the signal that interrupted the program.
+	Signal os.Signal
+}
+
+// Error implements the error interface.
+//
+// Message: "interrupted by signal {{ .Signal }}"
+func (e *ErrInterrupted) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("interrupted")
+
+	if e.Signal != nil {
+		builder.WriteString(" by signal ")
+		builder.WriteString(e.Signal.String())
+	}
+
+	return builder.String()
+}
+
+// ExitCode implements the ExitCoder interface. Following the shell convention, it
+// is 128 plus the number of the signal. (i.e., 130 for SIGINT)
+func (e *ErrInterrupted) ExitCode() int {
+	sig, ok := e.Signal.(syscall.Signal)
+	if !ok {
+		return ExitFailure
+	}
+
+	return 128 + int(sig)
+}
+
+// NewErrInterrupted creates a new ErrInterrupted.
+//
+// Parameters:
+//   - signal: The signal that interrupted the program.
+//
+// Returns:
+//   - *ErrInterrupted: The new error. Never returns nil.
+func NewErrInterrupted(signal os.Signal) *ErrInterrupted {
+	return &ErrInterrupted{
+		Signal: signal,
+	}
+}
+
+// ErrBatchFailed is an error that is returned when commands of a batch failed.
+type ErrBatchFailed struct {
+	// Failed is the number of commands that failed.
+	Failed int
+
+	// Total is the number of commands that ran.
+	Total int
+
+	// Code is the exit code of the batch.
+	Code int
+}
+
+// Error implements the error interface.
+//
+// Message: "{{ .Failed }} of {{ .Total }} commands failed"
+func (e *ErrBatchFailed) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString(strconv.Itoa(e.Failed))
+	builder.WriteString(" of ")
+	builder.WriteString(strconv.Itoa(e.Total))
+
+	if e.Total == 1 {
+		builder.WriteString(" command failed")
+	} else {
+		builder.WriteString(" commands failed")
+	}
+
+	return builder.String()
+}
+
+// ExitCode implements the ExitCoder interface.
+func (e *ErrBatchFailed) ExitCode() int {
+	return e.Code
+}
+
+// NewErrBatchFailed creates a new ErrBatchFailed.
+//
+// Parameters:
+//   - failed: The number of commands that failed.
+//   - total: The number of commands that ran.
+//   - code: The exit code of the batch.
+//
+// Returns:
+//   - *ErrBatchFailed: The new error. Never returns nil.
+func NewErrBatchFailed(failed, total, code int) *ErrBatchFailed {
+	return &ErrBatchFailed{
+		Failed: failed,
+		Total:  total,
+		Code:   code,
+	}
+}
+
+// ErrInvalidEnv is an error that is returned when an environment variable holds an
+// invalid value for the argument or flag that falls back to it.
+type ErrInvalidEnv struct {
+	// Variable is the name of the environment variable.
+	Variable string
+
+	// Value is the value of the environment variable.
+	Value string
+
+	// Reason is the reason why the value is invalid.
+	Reason error
+}
+
+// Error implements the error interface.
+//
+// Message: "environment variable {{ .Variable }} has invalid value {{ .Value }}: {{ .Reason }}"
+func (e *ErrInvalidEnv) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("environment variable ")
+	builder.WriteString(e.Variable)
+	builder.WriteString(" has invalid value ")
+	builder.WriteString(strconv.Quote(e.Value))
+
+	if e.Reason != nil {
+		builder.WriteString(": ")
+		builder.WriteString(e.Reason.Error())
+	}
+
+	return builder.String()
+}
+
+// Unwrap returns the reason of the error.
+//
+// Returns:
+//   - error: The reason of the error.
+func (e *ErrInvalidEnv) Unwrap() error {
+	return e.Reason
+}
+
+// NewErrInvalidEnv creates a new ErrInvalidEnv.
+//
+// Parameters:
+//   - variable: The name of the environment variable.
+//   - value: The value of the environment variable.
+//   - reason: The reason why the value is invalid.
+//
+// Returns:
+//   - *ErrInvalidEnv: The new error. Never returns nil.
+func NewErrInvalidEnv(variable, value string, reason error) *ErrInvalidEnv {
+	return &ErrInvalidEnv{
+		Variable: variable,
+		Value:    value,
+		Reason:   reason,
+	}
+}
+
+// ErrInvalidSetting is an error that is returned when a setting of a configuration
+// file holds an invalid value for the argument or flag it configures.
+type ErrInvalidSetting struct {
+	// Key is the dotted key of the setting.
+	Key string
+
+	// Origin is the path of the configuration file.
+	Origin string
+
+	// Reason is the reason why the value is invalid.
+	Reason error
+}
+
+// Error implements the error interface.
+//
+// Message: "setting {{ .Key }} of {{ .Origin }} is invalid: {{ .Reason }}"
+func (e *ErrInvalidSetting) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("setting ")
+	builder.WriteString(strconv.Quote(e.Key))
+
+	if e.Origin != "" {
+		builder.WriteString(" of ")
+		builder.WriteString(strconv.Quote(e.Origin))
+	}
+
+	builder.WriteString(" is invalid")
+
+	if e.Reason != nil {
+		builder.WriteString(": ")
+		builder.WriteString(e.Reason.Error())
+	}
+
+	return builder.String()
+}
+
+// Unwrap returns the reason of the error.
+//
+// Returns:
+//   - error: The reason of the error.
+func (e *ErrInvalidSetting) Unwrap() error {
+	return e.Reason
+}
+
+// NewErrInvalidSetting creates a new ErrInvalidSetting.
+//
+// Parameters:
+//   - key: The dotted key of the setting.
+//   - origin: The path of the configuration file.
+//   - reason: The reason why the value is invalid.
+//
+// Returns:
+//   - *ErrInvalidSetting: The new error. Never returns nil.
+func NewErrInvalidSetting(key, origin string, reason error) *ErrInvalidSetting {
+	return &ErrInvalidSetting{
+		Key:    key,
+		Origin: origin,
+		Reason: reason,
+	}
+}
+
+// ErrUnknownSetting is an error that is returned when a setting is not registered.
+type ErrUnknownSetting struct {
+	// Key is the key of the unknown setting.
+	Key string
+
+	// Suggestions is the list of registered keys that are similar to the unknown
+	// one, closest first.
+	Suggestions []string
+}
+
+// Error implements the error interface.
+//
+// Message: "setting {{ .Key }} not found"
+func (e *ErrUnknownSetting) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString("setting ")
+	builder.WriteString(strconv.Quote(e.Key))
+	builder.WriteString(" not found")
+
+	return builder.String()
+}
+
+// NewErrUnknownSetting creates a new ErrUnknownSetting.
+//
+// Parameters:
+//   - key: The key of the unknown setting.
+//   - suggestions: The registered keys that are similar to the unknown one.
+//
+// Returns:
+//   - *ErrUnknownSetting: The new error. Never returns nil.
+func NewErrUnknownSetting(key string, suggestions []string) *ErrUnknownSetting {
+	return &ErrUnknownSetting{
+		Key:         key,
+		Suggestions: suggestions,
+	}
+}
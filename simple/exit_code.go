@@ -0,0 +1,145 @@
+package simple
+
+import "errors"
+
+const (
+	// ExitSuccess is the exit code of a program that ran successfully.
+	ExitSuccess int = 0
+
+	// ExitFailure is the exit code of a program whose command failed.
+	ExitFailure int = 1
+
+	// ExitUsage is the exit code of a program that was used incorrectly; such as
+	// an unknown command, a wrong number of arguments, an invalid flag or an invalid
+	// environment variable or setting. (See IsUsageError)
+	ExitUsage int = 2
+)
+
+// ExitCoder is implemented by errors that carry their own exit code.
+type ExitCoder interface {
+	// ExitCode returns the exit code of the error.
+	//
+	// Returns:
+	//   - int: The exit code.
+	ExitCode() int
+}
+
+// ExitCodeRule maps an error to an exit code.
+//
+// Parameters:
+//   - err: The error to map. Never nil.
+//
+// Returns:
+//   - int: The exit code.
+//   - bool: True if the rule applies to the error, false otherwise.
+type ExitCodeRule func(err error) (int, bool)
+
+// OnError returns a rule that maps every error of type T, anywhere in the chain of
+// wrapped errors, to the given exit code.
+//
+// Parameters:
+//   - code: The exit code.
+//
+// Returns:
+//   - ExitCodeRule: The rule. Never returns nil.
+func OnError[T error](code int) ExitCodeRule {
+	return func(err error) (int, bool) {
+		var target T
+
+		ok := errors.As(err, &target)
+		if !ok {
+			return 0, false
+		}
+
+		return code, true
+	}
+}
+
+// ExitCodeOf returns the exit code of the given error.
+//
+// The rules are tried in order and the first one that applies wins. Otherwise, errors
+// that implement ExitCoder use their own exit code, usage errors map to ExitUsage and
+// any other error maps to ExitFailure.
+//
+// Parameters:
+//   - err: The error.
+//   - rules: The rules to try first.
+//
+// Returns:
+//   - int: The exit code. ExitSuccess if err is nil.
+func ExitCodeOf(err error, rules []ExitCodeRule) int {
+	if err == nil {
+		return ExitSuccess
+	}
+
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+
+		code, ok := rule(err)
+		if ok {
+			return code
+		}
+	}
+
+	var coder ExitCoder
+
+	ok := errors.As(err, &coder)
+	if ok {
+		return coder.ExitCode()
+	}
+
+	ok = IsUsageError(err)
+	if ok {
+		return ExitUsage
+	}
+
+	return ExitFailure
+}
+
+// IsUsageError checks whether the given error is caused by an incorrect use of the
+// program rather than by the failure of a command. The program is used incorrectly
+// when any of its inputs is invalid; be it the command line, an environment
+// variable or a setting of the configuration files.
+//
+// Parameters:
+//   - err: The error to check.
+//
+// Returns:
+//   - bool: True if the error is a usage error, false otherwise.
+func IsUsageError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var (
+		no_command      *ErrNoCommand
+		unknown_command *ErrUnknownCommand
+		ambiguous       *ErrAmbiguousCommand
+		few_args        *ErrFewArguments
+		many_args       *ErrManyArguments
+		invalid_arg     *ErrInvalidArgument
+		flag_not_found  *ErrFlagNotFound
+		flag_missing    *ErrFlagMissingArg
+		invalid_flag    *ErrInvalidFlag
+		flag_conflict   *ErrFlagConflict
+		invalid_env     *ErrInvalidEnv
+		invalid_setting *ErrInvalidSetting
+		unknown_setting *ErrUnknownSetting
+	)
+
+	return errors.As(err, &no_command) ||
+		errors.As(err, &unknown_command) ||
+		errors.As(err, &ambiguous) ||
+		errors.As(err, &few_args) ||
+		errors.As(err, &many_args) ||
+		errors.As(err, &invalid_arg) ||
+		errors.As(err, &flag_not_found) ||
+		errors.As(err, &flag_missing) ||
+		errors.As(err, &invalid_flag) ||
+		errors.As(err, &flag_conflict) ||
+		errors.As(err, &invalid_env) ||
+		errors.As(err, &invalid_setting) ||
+		errors.As(err, &unknown_setting)
+}
@@ -0,0 +1,80 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// coded_error is an error that carries its own exit code.
+type coded_error struct {
+	code   int
+	reason error
+}
+
+// Error implements the error interface.
+func (e *coded_error) Error() string {
+	return fmt.Sprintf("exit %d", e.code)
+}
+
+// Unwrap returns the reason of the error.
+func (e *coded_error) Unwrap() error {
+	return e.reason
+}
+
+// ExitCode implements the ExitCoder interface.
+func (e *coded_error) ExitCode() int {
+	return e.code
+}
+
+func TestExitCodeOf(t *testing.T) {
+	few_args := NewErrFewArguments(2, 1)
+
+	tests := []struct {
+		name  string
+		err   error
+		rules []ExitCodeRule
+		want  int
+	}{
+		{"no error", nil, nil, ExitSuccess},
+		{"failure", errors.New("boom"), nil, ExitFailure},
+		{"usage", few_args, nil, ExitUsage},
+		{"wrapped usage", fmt.Errorf("add: %w", few_args), nil, ExitUsage},
+		{"invalid environment", NewErrInvalidEnv("TOOL_N", "x", errors.New("nan")), nil, ExitUsage},
+		{"invalid setting", NewErrInvalidSetting("greet.n", "config.json", errors.New("nan")), nil, ExitUsage},
+		{"unknown setting", NewErrUnknownSetting("nope", nil), nil, ExitUsage},
+		{"exit coder", &coded_error{code: 7}, nil, 7},
+		{"exit coder over usage", &coded_error{code: 7, reason: few_args}, nil, 7},
+		{"wrapped exit coder", fmt.Errorf("run: %w", &coded_error{code: 9}), nil, 9},
+		{"rule over exit coder", &coded_error{code: 7}, []ExitCodeRule{OnError[*coded_error](64)}, 64},
+		{"rule over usage", few_args, []ExitCodeRule{OnError[*ErrFewArguments](64)}, 64},
+		{"first rule wins", few_args, []ExitCodeRule{
+			OnError[*ErrFewArguments](64),
+			OnError[*ErrFewArguments](65),
+		}, 64},
+		{"rule does not apply", errors.New("boom"), []ExitCodeRule{OnError[*ErrFewArguments](64)}, ExitFailure},
+		{"nil rule", few_args, []ExitCodeRule{nil}, ExitUsage},
+		{"custom rule", errors.New("boom"), []ExitCodeRule{func(error) (int, bool) { return 3, true }}, 3},
+	}
+
+	for _, tt := range tests {
+		got := ExitCodeOf(tt.err, tt.rules)
+		if got != tt.want {
+			t.Errorf("ExitCodeOf(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOnError(t *testing.T) {
+	rule := OnError[*ErrManyArguments](42)
+
+	code, ok := rule(fmt.Errorf("wrapped: %w", NewErrManyArguments(1, 2)))
+	if !ok || code != 42 {
+		t.Errorf("rule(*ErrManyArguments) = (%d, %v), want (42, true)", code, ok)
+	}
+
+	_, ok = rule(NewErrFewArguments(2, 1))
+	if ok {
+		t.Error("rule(*ErrFewArguments): the rule must not apply")
+	}
+}
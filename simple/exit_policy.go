@@ -0,0 +1,300 @@
+package simple
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PauseMode specifies when the exit sequence waits for the user to press ENTER.
+type PauseMode int
+
+const (
+	// PauseAuto pauses only when both stdin and stdout are terminals, the program
+	// does not run in a CI environment and the pause is not disabled by the
+	// environment variable of the policy.
+	PauseAuto PauseMode = iota
+
+	// PauseAlways always pauses.
+	PauseAlways
+
+	// PauseNever never pauses.
+	PauseNever
+)
+
+const (
+	// DefaultNoPauseEnv is the default name of the environment variable that
+	// disables the pause of the exit sequence.
+	DefaultNoPauseEnv string = "LYNECML_NO_PAUSE"
+
+	// DefaultSuccessMessage is the default message printed when the command ran
+	// successfully.
+	DefaultSuccessMessage string = "Command ran successfully"
+)
+
+var (
+	// DefaultExitPolicy is the exit policy used by DefaultExitSequence.
+	DefaultExitPolicy *ExitPolicy
+)
+
+func init() {
+	DefaultExitPolicy = &ExitPolicy{
+		Pause:          PauseAuto,
+		NoPauseEnv:     DefaultNoPauseEnv,
+		SuccessMessage: DefaultSuccessMessage,
+	}
+}
+
+// ExitPolicy is a struct that configures the exit sequence of a program.
+type ExitPolicy struct {
+	// Pause specifies when to wait for the user to press ENTER before exiting.
+	Pause PauseMode
+
+	// NoPauseEnv is the name of the environment variable that, when set to a true
+	// value (i.e., "1", "true"), disables the pause in PauseAuto mode. Leave empty
+	// to ignore the environment.
+	NoPauseEnv string
+
+	// QuietSuccess is true if nothing should be printed when the command ran
+	// successfully.
+	QuietSuccess bool
+
+	// SuccessMessage is the message printed when the command ran successfully. If
+	// empty, DefaultSuccessMessage is used.
+	SuccessMessage string
+
+	// ExitFn is the function that terminates the process. If nil, os.Exit is used.
+	ExitFn func(code int)
+}
+
+// Fix implements the errors.Fixer interface.
+func (ep *ExitPolicy) Fix() error {
+	if ep == nil {
+		return nil
+	}
+
+	ep.NoPauseEnv = strings.TrimSpace(ep.NoPauseEnv)
+
+	if ep.SuccessMessage == "" {
+		ep.SuccessMessage = DefaultSuccessMessage
+	}
+
+	if ep.ExitFn == nil {
+		ep.ExitFn = os.Exit
+	}
+
+	return nil
+}
+
+// Exit runs the exit sequence on the standard streams: it reports the outcome of the
+// command, waits for the user to press ENTER if needed and exits the process with
+// the given exit code.
+//
+// Errors and the success message are printed to stderr, so that the output of the
+// command, such as a completion script, is never mixed with them. Write failures are
+// ignored so that the process always exits.
+//
+// Parameters:
+//   - err: The error returned by the command. Nil if the command ran successfully.
+//   - exit_code: The exit code.
+func (ep ExitPolicy) Exit(err error, exit_code int) {
+	ep.ExitWith(os.Stdin, os.Stdout, os.Stderr, err, exit_code)
+}
+
+// ExitWith is like Exit but runs the exit sequence on the given streams.
+//
+// Parameters:
+//   - in: The input stream.
+//   - out: The output stream.
+//   - err_out: The error stream.
+//   - err: The error returned by the command. Nil if the command ran successfully.
+//   - exit_code: The exit code.
+func (ep ExitPolicy) ExitWith(in io.Reader, out, err_out io.Writer, err error, exit_code int) {
+	ep.exit(in, out, err_out, os.LookupEnv, err, exit_code)
+}
+
+// exit is like ExitWith but looks up environment variables with the given function.
+//
+// Parameters:
+//   - in: The input stream.
+//   - out: The output stream.
+//   - err_out: The error stream.
+//   - lookup_env: The function used to look up environment variables.
+//   - err: The error returned by the command. Nil if the command ran successfully.
+//   - exit_code: The exit code.
+func (ep ExitPolicy) exit(in io.Reader, out, err_out io.Writer, lookup_env func(string) (string, bool), err error, exit_code int) {
+	ep.report(err_out, err)
+
+	if ep.should_pause(in, out, lookup_env) {
+		pause(in, out)
+	}
+
+	exit_fn := ep.ExitFn
+	if exit_fn == nil {
+		exit_fn = os.Exit
+	}
+
+	exit_fn(exit_code)
+}
+
+// report prints the outcome of the command.
+//
+// Parameters:
+//   - err_out: The writer of errors and of the success message.
+//   - err: The error returned by the command.
+func (ep ExitPolicy) report(err_out io.Writer, err error) {
+	if err != nil {
+		_, _ = fmt.Fprintln(err_out, err.Error())
+
+		var (
+			unknown     *ErrUnknownCommand
+			suggestions []string
+		)
+
+		if errors.As(err, &unknown) {
+			suggestions = unknown.Suggestions
+		} else {
+			var unknown_setting *ErrUnknownSetting
+
+			if errors.As(err, &unknown_setting) {
+				suggestions = unknown_setting.Suggestions
+			}
+		}
+
+		if len(suggestions) > 0 {
+			_, _ = fmt.Fprintln(err_out)
+			_, _ = fmt.Fprintln(err_out, "Did you mean this?")
+
+			for _, suggestion := range suggestions {
+				_, _ = fmt.Fprintln(err_out, "\t"+suggestion)
+			}
+
+			_, _ = fmt.Fprintln(err_out)
+		}
+
+		if IsUsageError(err) {
+			_, _ = fmt.Fprintln(err_out, "Use \"help\" command to see the list of available commands")
+		}
+
+		return
+	}
+
+	if ep.QuietSuccess {
+		return
+	}
+
+	msg := ep.SuccessMessage
+	if msg == "" {
+		msg = DefaultSuccessMessage
+	}
+
+	_, _ = fmt.Fprintln(err_out, msg)
+}
+
+// should_pause checks whether the exit sequence must wait for the user.
+//
+// Parameters:
+//   - in: The input stream.
+//   - out: The output stream.
+//   - lookup_env: The function used to look up environment variables.
+//
+// Returns:
+//   - bool: True if the exit sequence must pause, false otherwise.
+func (ep ExitPolicy) should_pause(in io.Reader, out io.Writer, lookup_env func(string) (string, bool)) bool {
+	switch ep.Pause {
+	case PauseAlways:
+		return true
+	case PauseNever:
+		return false
+	}
+
+	if !IsTerminal(in) || !IsTerminal(out) {
+		return false
+	}
+
+	if IsCI(lookup_env) {
+		return false
+	}
+
+	if ep.NoPauseEnv != "" && lookup_env != nil {
+		value, ok := lookup_env(ep.NoPauseEnv)
+		if ok && is_truthy(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pause waits for the user to press ENTER. I/O errors, such as a closed stdin, end
+// the wait.
+//
+// Parameters:
+//   - in: The input stream.
+//   - out: The output stream.
+func pause(in io.Reader, out io.Writer) {
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintln(out, "Press ENTER to exit...")
+
+	_, _ = bufio.NewReader(in).ReadString('\n')
+}
+
+// IsTerminal checks whether the given stream is a terminal.
+//
+// Parameters:
+//   - stream: The stream to check.
+//
+// Returns:
+//   - bool: True if the stream is an *os.File attached to a terminal, false otherwise.
+//     Other character devices, such as /dev/null, are not terminals.
+func IsTerminal(stream any) bool {
+	f, ok := stream.(*os.File)
+	if !ok || f == nil {
+		return false
+	}
+
+	return is_terminal(f)
+}
+
+// IsCI checks whether the program runs in a continuous integration environment;
+// that is, whether the "CI" environment variable is set to a true value.
+//
+// Parameters:
+//   - lookup_env: The function used to look up environment variables.
+//
+// Returns:
+//   - bool: True if the program runs in a CI environment, false otherwise.
+func IsCI(lookup_env func(key string) (string, bool)) bool {
+	if lookup_env == nil {
+		return false
+	}
+
+	value, ok := lookup_env("CI")
+	return ok && is_truthy(value)
+}
+
+// is_truthy checks whether the given string represents a true value. Any
+// non-empty value that is not a false boolean is true.
+//
+// Parameters:
+//   - value: The string to check.
+//
+// Returns:
+//   - bool: True if the value is truthy, false otherwise.
+func is_truthy(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return true
+	}
+
+	return b
+}
@@ -0,0 +1,377 @@
+package simple
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ShortFlagPrefix is the prefix for short flags.
+	ShortFlagPrefix string = "-"
+
+	// LongFlagPrefix is the prefix for long flags.
+	LongFlagPrefix string = "--"
+
+	// FlagTerminator is the argument that marks the end of the flags. Every
+	// argument that follows it is treated as a positional argument.
+	FlagTerminator string = "--"
+)
+
+// Valuer is the interface to a value that can be set from a string.
+type Valuer interface {
+	// Set is a method that sets the value from a string.
+	//
+	// Parameters:
+	//   - str: The string to set the value from.
+	//
+	// Returns:
+	//   - error: An error if the value failed to set.
+	Set(str string) error
+}
+
+// BoolFlager is the interface that specifies a boolean flag.
+type BoolFlager interface {
+	// IsBoolFlag is a method that checks if the flag is a boolean flag.
+	//
+	// Returns:
+	//   - bool: True if the flag is a boolean flag. False otherwise.
+	IsBoolFlag() bool
+}
+
+// Getter is the interface to a value that can be retrieved.
+type Getter interface {
+	// Get is a method that returns the current value.
+	//
+	// Returns:
+	//   - any: The current value.
+	Get() any
+}
+
+// Resetter is the interface to a value that can be reset to its default.
+type Resetter interface {
+	// Reset is a method that resets the value to its default.
+	Reset()
+}
+
+// FlagOption is an option for a flag.
+//
+// Parameters:
+//   - flag: The flag to apply the option to.
+type FlagOption func(flag *Flag)
+
+// WithShortName sets the short name of the flag.
+//
+// Parameters:
+//   - short_name: The short name of the flag.
+//
+// Returns:
+//   - FlagOption: The option to apply to the flag.
+//
+// Successive calls to this option will replace the previous short name.
+func WithShortName(short_name rune) FlagOption {
+	f := func(flag *Flag) {
+		flag.short_name = short_name
+	}
+
+	return f
+}
+
+// WithValueName sets the name of the value of the flag as shown in the help.
+// (i.e., "--output <file>")
+//
+// Parameters:
+//   - name: The name of the value.
+//
+// Returns:
+//   - FlagOption: The option to apply to the flag.
+//
+// Successive calls to this option will replace the previous value name.
+func WithValueName(name string) FlagOption {
+	f := func(flag *Flag) {
+		flag.value_name = strings.TrimSpace(name)
+	}
+
+	return f
+}
+
+// WithConflicts declares the flags that cannot be used together with the flag.
+//
+// Parameters:
+//   - long_names: The long names of the conflicting flags.
+//
+// Returns:
+//   - FlagOption: The option to apply to the flag.
+//
+// Successive calls to this option will append to the previous conflicts.
+func WithConflicts(long_names ...string) FlagOption {
+	f := func(flag *Flag) {
+		flag.conflicts = append(flag.conflicts, long_names...)
+	}
+
+	return f
+}
+
+// WithEnv sets the environment variable the flag falls back to when it is not set on
+// the command line. Use EnvNone to opt out of the automatic name. (See
+// Program.AutoEnv)
+//
+// Parameters:
+//   - name: The name of the environment variable.
+//
+// Returns:
+//   - FlagOption: The option to apply to the flag.
+//
+// Successive calls to this option will replace the previous environment variable.
+func WithEnv(name string) FlagOption {
+	f := func(flag *Flag) {
+		flag.env = strings.TrimSpace(name)
+	}
+
+	return f
+}
+
+// Flag is the component of a generic flag.
+type Flag struct {
+	// long_name is the long name of the flag. (i.e., "--flag")
+	long_name string
+
+	// short_name is the short name of the flag. (i.e., "-f")
+	short_name rune
+
+	// brief is a brief description of the flag.
+	brief string
+
+	// value_name is the name of the value of the flag as shown in the help.
+	value_name string
+
+	// value is the current value of the flag after it has been parsed.
+	value Valuer
+
+	// conflicts is the list of long names of the flags that cannot be used
+	// together with this flag.
+	conflicts []string
+
+	// env is the environment variable the flag falls back to. Empty for the
+	// automatic name.
+	env string
+
+	// source is the source of the value of the flag during the last parse.
+	source ValueSource
+}
+
+// LongName returns the long name of the flag.
+//
+// Returns:
+//   - string: The long name of the flag, without the "--" prefix.
+func (f Flag) LongName() string {
+	return f.long_name
+}
+
+// ShortName returns the short name of the flag.
+//
+// Returns:
+//   - rune: The short name of the flag. 0 if the flag has no short name.
+func (f Flag) ShortName() rune {
+	return f.short_name
+}
+
+// Brief returns the brief description of the flag.
+//
+// Returns:
+//   - string: The brief description of the flag.
+func (f Flag) Brief() string {
+	return f.brief
+}
+
+// Changed checks whether the flag was set on the command line during the last parse.
+//
+// Returns:
+//   - bool: True if the flag was set, false otherwise.
+func (f Flag) Changed() bool {
+	return f.source == SourceCommandLine
+}
+
+// Source returns the source of the value of the flag during the last parse.
+//
+// Returns:
+//   - ValueSource: The source of the value.
+func (f Flag) Source() ValueSource {
+	return f.source
+}
+
+// Env returns the environment variable declared with WithEnv.
+//
+// Returns:
+//   - string: The environment variable. Empty if none was declared.
+func (f Flag) Env() string {
+	return f.env
+}
+
+// set is a helper method that sets the value of the flag and marks it as changed.
+//
+// Parameters:
+//   - is_short: True if the flag was written as a short flag. False otherwise.
+//   - str: The string to set the value from.
+//
+// Returns:
+//   - error: An error if the value is invalid.
+func (f *Flag) set(is_short bool, str string) error {
+	err := f.value.Set(str)
+	if err != nil {
+		return NewErrInvalidFlag(is_short, f, err)
+	}
+
+	f.source = SourceCommandLine
+
+	return nil
+}
+
+// IsBool checks whether the flag is a boolean flag; that is, a flag that does not
+// take an argument.
+//
+// Returns:
+//   - bool: True if the flag is a boolean flag, false otherwise.
+func (f Flag) IsBool() bool {
+	b, ok := f.value.(BoolFlager)
+	return ok && b.IsBoolFlag()
+}
+
+// String is a method that returns the string representation of the flag as shown
+// in the help. (i.e., "-o, --output <file>")
+//
+// Returns:
+//   - string: The string representation of the flag.
+func (f Flag) String() string {
+	var builder strings.Builder
+
+	if f.short_name != 0 {
+		builder.WriteString(ShortFlagPrefix)
+		builder.WriteRune(f.short_name)
+		builder.WriteString(", ")
+	}
+
+	builder.WriteString(LongFlagPrefix)
+	builder.WriteString(f.long_name)
+
+	if !f.IsBool() {
+		name := f.value_name
+		if name == "" {
+			name = "value"
+		}
+
+		builder.WriteString(" <")
+		builder.WriteString(name)
+		builder.WriteRune('>')
+	}
+
+	return builder.String()
+}
+
+// bool_value is a wrapper for bool type.
+type bool_value struct {
+	// value is the current value of the flag after it has been parsed.
+	value bool
+
+	// def_value is the default value of the flag.
+	def_value bool
+}
+
+// Set implements the Valuer interface.
+//
+// An empty string sets the value to true.
+func (b *bool_value) Set(str string) error {
+	if str == "" {
+		b.value = true
+
+		return nil
+	}
+
+	str = strings.ToLower(str)
+
+	switch str {
+	case "0", "false", "f":
+		b.value = false
+	case "1", "true", "t":
+		b.value = true
+	default:
+		return fmt.Errorf("invalid value: %s", str)
+	}
+
+	return nil
+}
+
+// IsBoolFlag implements the BoolFlager interface.
+//
+// Returns:
+//   - bool: True.
+func (b *bool_value) IsBoolFlag() bool {
+	return true
+}
+
+// Get implements the Getter interface.
+func (b *bool_value) Get() any {
+	return b.value
+}
+
+// Reset implements the Resetter interface.
+func (b *bool_value) Reset() {
+	b.value = b.def_value
+}
+
+// int_value is a wrapper for int type.
+type int_value struct {
+	// value is the current value of the flag after it has been parsed.
+	value int
+
+	// def_value is the default value of the flag.
+	def_value int
+}
+
+// Set implements the Valuer interface.
+func (i *int_value) Set(str string) error {
+	num, err := strconv.Atoi(str)
+	if err != nil {
+		return err
+	}
+
+	i.value = num
+
+	return nil
+}
+
+// Get implements the Getter interface.
+func (i *int_value) Get() any {
+	return i.value
+}
+
+// Reset implements the Resetter interface.
+func (i *int_value) Reset() {
+	i.value = i.def_value
+}
+
+// string_value is a wrapper for string type.
+type string_value struct {
+	// value is the current value of the flag after it has been parsed.
+	value string
+
+	// def_value is the default value of the flag.
+	def_value string
+}
+
+// Set implements the Valuer interface.
+func (s *string_value) Set(str string) error {
+	s.value = str
+
+	return nil
+}
+
+// Get implements the Getter interface.
+func (s *string_value) Get() any {
+	return s.value
+}
+
+// Reset implements the Resetter interface.
+func (s *string_value) Reset() {
+	s.value = s.def_value
+}
@@ -0,0 +1,436 @@
+package simple
+
+import (
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// FlagSet is a set of flags.
+type FlagSet struct {
+	// flag_list is the list of flags in the set, in registration order.
+	flag_list []*Flag
+}
+
+// NewFlagSet creates a new flag set.
+//
+// Returns:
+//   - *FlagSet: A pointer to the new flag set. Never returns nil.
+func NewFlagSet() *FlagSet {
+	return &FlagSet{}
+}
+
+// AddFlag adds a flag to the flag set. Does nothing if the flag is nil.
+// Panics if a flag with the same long or short name already exists.
+//
+// Parameters:
+//   - flag: The flag to add.
+func (fs *FlagSet) AddFlag(flag *Flag) {
+	if fs == nil || flag == nil {
+		return
+	}
+
+	if fs.long_flag(flag.long_name) != nil {
+		panic(fmt.Sprintf("flag --%s already exists", flag.long_name))
+	}
+
+	if flag.short_name != 0 && fs.short_flag(flag.short_name) != nil {
+		panic(fmt.Sprintf("flag -%c already exists", flag.short_name))
+	}
+
+	fs.flag_list = append(fs.flag_list, flag)
+}
+
+// Bool creates a new bool flag. Panics if the long name is empty.
+//
+// Parameters:
+//   - long_name: The long name of the flag.
+//   - def_value: The default value of the flag.
+//   - brief: A brief description of the flag.
+//   - opts: A list of options for the flag.
+//
+// Returns:
+//   - *bool: A pointer to the boolean value of the flag. Never returns nil.
+func (fs *FlagSet) Bool(long_name string, def_value bool, brief string, opts ...FlagOption) *bool {
+	value := &bool_value{
+		value:     def_value,
+		def_value: def_value,
+	}
+
+	fs.Var(long_name, value, brief, opts...)
+
+	return &value.value
+}
+
+// Int creates a new int flag. Panics if the long name is empty.
+//
+// Parameters:
+//   - long_name: The long name of the flag.
+//   - def_val: The default value of the flag.
+//   - brief: A brief description of the flag.
+//   - opts: A list of options for the flag.
+//
+// Returns:
+//   - *int: A pointer to the int value of the flag. Never returns nil.
+func (fs *FlagSet) Int(long_name string, def_val int, brief string, opts ...FlagOption) *int {
+	value := &int_value{
+		value:     def_val,
+		def_value: def_val,
+	}
+
+	fs.Var(long_name, value, brief, opts...)
+
+	return &value.value
+}
+
+// String creates a new string flag. Panics if the long name is empty.
+//
+// Parameters:
+//   - long_name: The long name of the flag.
+//   - def_val: The default value of the flag.
+//   - brief: A brief description of the flag.
+//   - opts: A list of options for the flag.
+//
+// Returns:
+//   - *string: A pointer to the string value of the flag. Never returns nil.
+func (fs *FlagSet) String(long_name string, def_val string, brief string, opts ...FlagOption) *string {
+	value := &string_value{
+		value:     def_val,
+		def_value: def_val,
+	}
+
+	fs.Var(long_name, value, brief, opts...)
+
+	return &value.value
+}
+
+// Var creates a new custom flag given a value type. Panics if the long name is empty or the value is nil.
+//
+// The value is only reset between two parses if it implements Resetter; otherwise,
+// it keeps the value of the previous parse. Values of programs that run more than
+// once, such as in the shell, in scripts or with simpletest, should implement it.
+//
+// Parameters:
+//   - long_name: The long name of the flag.
+//   - value: The value of the flag.
+//   - brief: A brief description of the flag.
+//   - opts: A list of options for the flag.
+func (fs *FlagSet) Var(long_name string, value Valuer, brief string, opts ...FlagOption) {
+	long_name = strings.TrimSpace(long_name)
+
+	if long_name == "" {
+		panic("long name cannot be empty")
+	} else if strings.HasPrefix(long_name, ShortFlagPrefix) {
+		panic("long name cannot start with '-'")
+	} else if value == nil {
+		panic("value cannot be nil")
+	}
+
+	flag := &Flag{
+		long_name: long_name,
+		brief:     strings.TrimSpace(brief),
+		value:     value,
+	}
+
+	for _, opt := range opts {
+		opt(flag)
+	}
+
+	if flag.short_name == '-' || flag.short_name == '=' {
+		panic(fmt.Sprintf("short name cannot be %q", flag.short_name))
+	}
+
+	fs.AddFlag(flag)
+}
+
+// Flags is a method that returns an iterator of the flags in registration order.
+//
+// Returns:
+//   - iter.Seq[*Flag]: The iterator of flags.
+func (fs FlagSet) Flags() iter.Seq[*Flag] {
+	return func(yield func(*Flag) bool) {
+		for _, flag := range fs.flag_list {
+			if !yield(flag) {
+				break
+			}
+		}
+	}
+}
+
+// Size returns the number of flags in the set.
+//
+// Returns:
+//   - int: The number of flags.
+func (fs FlagSet) Size() int {
+	return len(fs.flag_list)
+}
+
+// Reset marks every flag as unchanged and resets the values that implement the
+// Resetter interface to their default. Values of custom flags that do not implement
+// Resetter are kept.
+func (fs *FlagSet) Reset() {
+	if fs == nil {
+		return
+	}
+
+	for _, flag := range fs.flag_list {
+		flag.source = SourceDefault
+
+		resetter, ok := flag.value.(Resetter)
+		if ok {
+			resetter.Reset()
+		}
+	}
+}
+
+// long_flag finds the flag with the given long name.
+//
+// Parameters:
+//   - name: The long name of the flag.
+//
+// Returns:
+//   - *Flag: The flag. Nil if not found.
+func (fs FlagSet) long_flag(name string) *Flag {
+	for _, flag := range fs.flag_list {
+		if flag.long_name == name {
+			return flag
+		}
+	}
+
+	return nil
+}
+
+// short_flag finds the flag with the given short name.
+//
+// Parameters:
+//   - name: The short name of the flag.
+//
+// Returns:
+//   - *Flag: The flag. Nil if not found.
+func (fs FlagSet) short_flag(name rune) *Flag {
+	for _, flag := range fs.flag_list {
+		if flag.short_name == name {
+			return flag
+		}
+	}
+
+	return nil
+}
+
+// Parse parses the flags in the given arguments and returns the remaining positional
+// arguments.
+//
+// Format:
+//
+//	--flag		: only boolean flags without arguments
+//	--flag=value	: any flag
+//	--flag value 	: any non-boolean flag
+//
+//	-f		: only boolean flags without arguments
+//	-f=value	: any flag
+//	-f value 	: any non-boolean flag
+//	-fvalue 	: any non-boolean flag
+//	-abc		: multiple short flags; only the last one may take an argument
+//
+//	--		: every argument that follows is a positional argument
+//
+// Arguments that do not start with "-", a lone "-" and negative numbers are treated
+// as positional arguments.
+//
+// The set is reset before the arguments are parsed, so that a flag given in a
+// previous parse does not keep its value; except for custom values that do not
+// implement Resetter. (See Reset)
+//
+// Parameters:
+//   - args: The arguments to parse.
+//
+// Returns:
+//   - []string: The positional arguments, in order.
+//   - error: An error if the flags are invalid or if two conflicting flags are set.
+func (fs *FlagSet) Parse(args []string) ([]string, error) {
+	fs.Reset()
+
+	var positionals []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == FlagTerminator {
+			positionals = append(positionals, args[i+1:]...)
+			break
+		}
+
+		if strings.HasPrefix(arg, LongFlagPrefix) {
+			n, err := fs.parse_long(arg[len(LongFlagPrefix):], args[i+1:])
+			if err != nil {
+				return nil, err
+			}
+
+			i += n
+		} else if strings.HasPrefix(arg, ShortFlagPrefix) && arg != ShortFlagPrefix && !is_number(arg) {
+			n, err := fs.parse_short(arg[len(ShortFlagPrefix):], args[i+1:])
+			if err != nil {
+				return nil, err
+			}
+
+			i += n
+		} else {
+			positionals = append(positionals, arg)
+		}
+	}
+
+	err := fs.check_conflicts()
+	if err != nil {
+		return nil, err
+	}
+
+	return positionals, nil
+}
+
+// check_conflicts checks that no two conflicting flags were set.
+//
+// Returns:
+//   - error: An *ErrFlagConflict if two conflicting flags were set.
+func (fs FlagSet) check_conflicts() error {
+	for _, flag := range fs.flag_list {
+		if !flag.Changed() {
+			continue
+		}
+
+		for _, name := range flag.conflicts {
+			other := fs.long_flag(name)
+
+			if other != nil && other.Changed() {
+				return NewErrFlagConflict(flag.long_name, other.long_name, nil)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parse_long parses a single long flag.
+//
+// Parameters:
+//   - header: The flag without its prefix. (i.e., "name" or "name=value")
+//   - rest: The arguments that follow the flag.
+//
+// Returns:
+//   - int: The number of arguments of rest that were consumed.
+//   - error: An error if the flag is invalid.
+func (fs *FlagSet) parse_long(header string, rest []string) (int, error) {
+	name, value, has_value := strings.Cut(header, "=")
+
+	flag := fs.long_flag(name)
+	if flag == nil {
+		return 0, NewErrFlagNotFound(false, name)
+	}
+
+	var consumed int
+
+	if !has_value && !flag.IsBool() {
+		if len(rest) == 0 {
+			return 0, NewErrFlagMissingArg(false, flag)
+		}
+
+		value = rest[0]
+		consumed = 1
+	}
+
+	err := flag.set(false, value)
+	if err != nil {
+		return 0, err
+	}
+
+	return consumed, nil
+}
+
+// parse_short parses a bundle of short flags.
+//
+// Parameters:
+//   - header: The flags without their prefix. (i.e., "abc", "o=value" or "ovalue")
+//   - rest: The arguments that follow the flags.
+//
+// Returns:
+//   - int: The number of arguments of rest that were consumed.
+//   - error: An error if one of the flags is invalid.
+func (fs *FlagSet) parse_short(header string, rest []string) (int, error) {
+	names, value, has_value := strings.Cut(header, "=")
+
+	runes := []rune(names)
+	if len(runes) == 0 {
+		return 0, NewErrFlagNotFound(true, header)
+	}
+
+	for j, r := range runes {
+		flag := fs.short_flag(r)
+		if flag == nil {
+			return 0, NewErrFlagNotFound(true, string(r))
+		}
+
+		is_last := j == len(runes)-1
+
+		if flag.IsBool() {
+			var err error
+
+			if is_last && has_value {
+				err = flag.set(true, value)
+			} else {
+				err = flag.set(true, "")
+			}
+
+			if err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		var consumed int
+
+		if !is_last {
+			if has_value {
+				return 0, NewErrInvalidFlag(true, flag, fmt.Errorf("ambiguous value in %q", ShortFlagPrefix+header))
+			}
+
+			value = string(runes[j+1:])
+		} else if !has_value {
+			if len(rest) == 0 {
+				return 0, NewErrFlagMissingArg(true, flag)
+			}
+
+			value = rest[0]
+			consumed = 1
+		}
+
+		err := flag.set(true, value)
+		if err != nil {
+			return 0, err
+		}
+
+		return consumed, nil
+	}
+
+	return 0, nil
+}
+
+// is_number checks whether the given argument is a numeric literal; such as a
+// negative number that must not be mistaken for a short flag. Words that
+// strconv.ParseFloat accepts as well, such as "-inf" or "-nan", are not numeric
+// literals.
+//
+// Parameters:
+//   - arg: The argument to check.
+//
+// Returns:
+//   - bool: True if the argument is a numeric literal, false otherwise.
+func is_number(arg string) bool {
+	digits := strings.TrimPrefix(strings.TrimPrefix(arg, ShortFlagPrefix), ".")
+	if digits == "" || digits[0] < '0' || digits[0] > '9' {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
@@ -0,0 +1,151 @@
+package simple
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// new_test_flag_set creates the flag set used by the tests of this file.
+func new_test_flag_set() (*FlagSet, *bool, *int, *string) {
+	fs := NewFlagSet()
+
+	verbose := fs.Bool("verbose", false, "Verbose output", WithShortName('v'))
+	count := fs.Int("count", 1, "Number of times", WithShortName('n'))
+	name := fs.String("name", "world", "Name to greet", WithConflicts("verbose"))
+
+	return fs, verbose, count, name
+}
+
+func TestFlagSetParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		positionals []string
+		verbose     bool
+		count       int
+		str         string
+	}{
+		{"defaults", []string{"a", "b"}, []string{"a", "b"}, false, 1, "world"},
+		{"long bool", []string{"--verbose", "a"}, []string{"a"}, true, 1, "world"},
+		{"long equal", []string{"--count=3"}, nil, false, 3, "world"},
+		{"long separate", []string{"--name", "bob"}, nil, false, 1, "bob"},
+		{"short attached", []string{"-n5"}, nil, false, 5, "world"},
+		{"short combined", []string{"-vn", "2"}, nil, true, 2, "world"},
+		{"terminator", []string{"--", "-v"}, []string{"-v"}, false, 1, "world"},
+		{"negative number", []string{"-3"}, []string{"-3"}, false, 1, "world"},
+		{"lone dash", []string{"-"}, []string{"-"}, false, 1, "world"},
+		{"fraction", []string{"-.5"}, []string{"-.5"}, false, 1, "world"},
+		{"exponent", []string{"-1e3"}, []string{"-1e3"}, false, 1, "world"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, verbose, count, str := new_test_flag_set()
+
+			positionals, err := fs.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(positionals, tt.positionals) {
+				t.Errorf("positionals = %q, want %q", positionals, tt.positionals)
+			}
+
+			if *verbose != tt.verbose || *count != tt.count || *str != tt.str {
+				t.Errorf("values = (%v, %d, %q), want (%v, %d, %q)", *verbose, *count, *str, tt.verbose, tt.count, tt.str)
+			}
+		})
+	}
+}
+
+func TestFlagSetParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		target any
+	}{
+		{"unknown long", []string{"--nope"}, new(*ErrFlagNotFound)},
+		{"unknown short", []string{"-x"}, new(*ErrFlagNotFound)},
+		{"missing argument", []string{"--count"}, new(*ErrFlagMissingArg)},
+		{"invalid value", []string{"--count=abc"}, new(*ErrInvalidFlag)},
+		{"conflict", []string{"-v", "--name=bob"}, new(*ErrFlagConflict)},
+		{"infinity", []string{"-inf"}, new(*ErrFlagNotFound)},
+		{"not a number", []string{"-nan"}, new(*ErrInvalidFlag)},
+		{"long infinity", []string{"-Infinity"}, new(*ErrFlagNotFound)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, _, _, _ := new_test_flag_set()
+
+			_, err := fs.Parse(tt.args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if !errors.As(err, tt.target) {
+				t.Errorf("error = %v (%T), want %T", err, err, tt.target)
+			}
+		})
+	}
+}
+
+func TestFlagSetParseResets(t *testing.T) {
+	fs, verbose, count, _ := new_test_flag_set()
+
+	_, err := fs.Parse([]string{"-v", "--count=7"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = fs.Parse(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *verbose || *count != 1 {
+		t.Errorf("values after a second parse = (%v, %d), want the defaults", *verbose, *count)
+	}
+
+	for flag := range fs.Flags() {
+		if flag.Changed() {
+			t.Errorf("flag %q is still marked as changed", flag.LongName())
+		}
+	}
+}
+
+// list_value is a custom value that does not implement Resetter.
+type list_value struct {
+	values []string
+}
+
+// String implements the Valuer interface.
+func (l list_value) String() string {
+	return strings.Join(l.values, ",")
+}
+
+// Set implements the Valuer interface.
+func (l *list_value) Set(str string) error {
+	l.values = append(l.values, str)
+	return nil
+}
+
+func TestFlagSetParseKeepsCustomValues(t *testing.T) {
+	var list list_value
+
+	fs := NewFlagSet()
+	fs.Var("tag", &list, "Tags")
+
+	for _, args := range [][]string{{"--tag=a"}, {"--tag=b"}} {
+		_, err := fs.Parse(args)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if list.String() != "a,b" {
+		t.Errorf("value = %q, want %q", list.String(), "a,b")
+	}
+}
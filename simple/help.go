@@ -0,0 +1,359 @@
+package simple
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// new_help_command creates the built-in help command.
+//
+// Returns:
+//   - *Command: The help command. Never returns nil.
+func new_help_command() *Command {
+	pos := StringArg("command")
+	pos.Env = EnvNone
+
+	pos.CompleteFn = func(p *Program, _ string) []string {
+		return p.Complete([]string{""})
+	}
+
+	return &Command{
+		Name:     "help",
+		Brief:    "Displays help information about the program or a specific command",
+		RunFn:    run_help,
+		Argument: AtLeastNArgs(pos, 0),
+		builtin:  true,
+	}
+}
+
+// run_help is the run function of the help command.
+//
+// Parameters:
+//   - p: The program that runs the command.
+//   - args: The path of the command to display. Empty for the whole program.
+//
+// Returns:
+//   - error: An error if the help could not be printed.
+func run_help(p *Program, args []string) error {
+	if len(args) == 0 {
+		return p.print_help()
+	}
+
+	cmd, err := lookup_command(p.command_table, args[0], p.AllowPrefix)
+	if err != nil {
+		return err
+	} else if cmd == nil {
+		return NewErrUnknownCommand(args[0], p.suggest(args[0], p.command_table))
+	}
+
+	cmd, rest, err := cmd.resolve(args[1:], p.AllowPrefix)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return NewErrUnknownCommand(cmd.FullName()+" "+rest[0], p.suggest(rest[0], cmd.sub_commands))
+	}
+
+	return p.print_command_help(cmd)
+}
+
+// print_help prints the usage of the program followed by the list of all of its
+// commands.
+//
+// Returns:
+//   - error: An error if the help could not be printed.
+func (p *Program) print_help() error {
+	_, err := fmt.Fprintln(p, "Usage:", p.Name, p.usage())
+	if err != nil {
+		return err
+	}
+
+	return p.print_commands(p.top_commands(), true)
+}
+
+// print_command_help prints the detailed usage page of the given command.
+//
+// Parameters:
+//   - cmd: The command to print. Assumed to not be nil.
+//
+// Returns:
+//   - error: An error if the help could not be printed.
+func (p *Program) print_command_help(cmd *Command) error {
+	var err error
+
+	if cmd.Brief == "" {
+		_, err = fmt.Fprintln(p, cmd.FullName())
+	} else {
+		_, err = fmt.Fprintln(p, cmd.FullName()+" — "+cmd.Brief)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if cmd.Deprecated != nil {
+		_, err = fmt.Fprintln(p)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p, "Deprecated:", cmd.Deprecated.Warning(cmd.FullName()))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintln(p)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(p, "Usage:")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(p, "  "+p.Name+" "+cmd.Usage())
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Aliases) > 0 {
+		_, err = fmt.Fprintln(p)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p, "Aliases:")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p, "  "+strings.Join(cmd.Aliases, ", "))
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.HasFlags() {
+		var rows [][]string
+
+		for flag := range cmd.flag_set.Flags() {
+			rows = append(rows, []string{flag.String(), flag.Brief()})
+		}
+
+		_, err = fmt.Fprintln(p)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p, "Flags:")
+		if err != nil {
+			return err
+		}
+
+		for _, line := range align_rows(rows, "  ") {
+			_, err := fmt.Fprintln(p, line)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	env_rows := p.env_rows(cmd)
+
+	if len(env_rows) > 0 {
+		_, err = fmt.Fprintln(p)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p, "Environment:")
+		if err != nil {
+			return err
+		}
+
+		for _, line := range align_rows(env_rows, "  ") {
+			_, err := fmt.Fprintln(p, line)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(cmd.Examples) > 0 {
+		_, err = fmt.Fprintln(p)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p, "Examples:")
+		if err != nil {
+			return err
+		}
+
+		for _, example := range cmd.Examples {
+			_, err := fmt.Fprintln(p, "  "+p.Name+" "+example)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return p.print_commands(ordered_commands(cmd.sub_commands, cmd.sub_order), false)
+}
+
+// print_commands prints the listing of the given commands under the heading of
+// their group. Commands with no group come first, under "Commands:"; the other
+// groups follow in order of first appearance.
+//
+// Parameters:
+//   - commands: The commands to list, in order. Nil and hidden commands are ignored.
+//   - recursive: Whether sub-commands are listed, under the group of their
+//     top-level command.
+//
+// Returns:
+//   - error: An error if the listing could not be printed.
+func (p *Program) print_commands(commands []*Command, recursive bool) error {
+	groups := []string{""}
+	table := make(map[string][][]string)
+
+	for _, cmd := range commands {
+		if cmd == nil || cmd.Hidden {
+			continue
+		}
+
+		if !slices.Contains(groups, cmd.Group) {
+			groups = append(groups, cmd.Group)
+		}
+
+		table[cmd.Group] = append(table[cmd.Group], command_rows([]*Command{cmd}, recursive)...)
+	}
+
+	var rows [][]string
+
+	for _, group := range groups {
+		rows = append(rows, table[group]...)
+	}
+
+	// Rows are aligned all at once so that the columns line up across groups.
+	lines := align_rows(rows, "  ")
+
+	for _, group := range groups {
+		size := len(table[group])
+		if size == 0 {
+			continue
+		}
+
+		heading := "Commands:"
+		if group != "" {
+			heading = group + ":"
+		}
+
+		_, err := fmt.Fprintln(p)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p, heading)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range lines[:size] {
+			_, err := fmt.Fprintln(p, line)
+			if err != nil {
+				return err
+			}
+		}
+
+		lines = lines[size:]
+	}
+
+	return nil
+}
+
+// command_rows returns the rows of the command listing; one row per command made
+// of its full name, the usage of its argument and its brief. Rows follow the order
+// of registration and the briefs of deprecated commands start with DeprecatedMark.
+//
+// Parameters:
+//   - commands: The commands to list. Nil and hidden commands are ignored.
+//   - recursive: Whether sub-commands are listed as well.
+//
+// Returns:
+//   - [][]string: The rows of the listing.
+func command_rows(commands []*Command, recursive bool) [][]string {
+	var rows [][]string
+
+	for _, cmd := range visible_commands(commands, recursive) {
+		brief := cmd.Brief
+
+		if cmd.Deprecated != nil {
+			brief = strings.TrimSpace(DeprecatedMark + " " + brief)
+		}
+
+		rows = append(rows, []string{cmd.FullName(), cmd.ArgumentUsage(), brief})
+	}
+
+	return rows
+}
+
+// visible_commands returns the visible commands in the given order. When
+// recursive, each command is followed by its own visible sub-commands, in order of
+// registration.
+//
+// Parameters:
+//   - commands: The commands to filter. Nil and hidden commands are ignored.
+//   - recursive: Whether sub-commands are included as well.
+//
+// Returns:
+//   - []*Command: The visible commands.
+func visible_commands(commands []*Command, recursive bool) []*Command {
+	var result []*Command
+
+	for _, cmd := range commands {
+		if cmd == nil || cmd.Hidden {
+			continue
+		}
+
+		result = append(result, cmd)
+
+		if recursive {
+			subs := ordered_commands(cmd.sub_commands, cmd.sub_order)
+			result = append(result, visible_commands(subs, true)...)
+		}
+	}
+
+	return result
+}
+
+// align_rows aligns the columns of the given rows.
+//
+// Parameters:
+//   - rows: The rows to align.
+//   - indent: The indentation to add at the beginning of each line.
+//
+// Returns:
+//   - []string: The aligned lines, without trailing spaces.
+func align_rows(rows [][]string, indent string) []string {
+	var builder strings.Builder
+
+	w := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+
+	for _, row := range rows {
+		_, _ = fmt.Fprintln(w, indent+strings.Join(row, "\t"))
+	}
+
+	_ = w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(builder.String(), "\n"), "\n")
+
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return lines
+}
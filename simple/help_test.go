@@ -0,0 +1,135 @@
+package simple
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// new_test_help_program creates the program used by the tests of the help. Its
+// commands are registered out of group order, and some of them are hidden.
+func new_test_help_program(out *bytes.Buffer) *Program {
+	remote := &Command{Name: "remote", Group: "Remotes", Brief: "Manages the remotes"}
+	remote.AddCommands(
+		&Command{Name: "add", Brief: "Adds a remote", Argument: NewArgument(StringArg("name"))},
+		&Command{Name: "prune", Brief: "Prunes the remotes", Hidden: true},
+	)
+
+	p := &Program{Name: "tool", Out: out, Err: out}
+
+	p.AddCommands(
+		&Command{Name: "gc", Group: "Maintenance", Brief: "Cleans up"},
+		remote,
+		&Command{Name: "status", Brief: "Shows the status"},
+		&Command{Name: "secret", Group: "Maintenance", Brief: "Hidden", Hidden: true},
+		&Command{Name: "fetch", Group: "Remotes", Brief: "Fetches"},
+		&Command{Name: "fsck", Group: "Maintenance", Brief: "Checks"},
+	)
+
+	return p
+}
+
+func TestHelpGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "program",
+			args: []string{"tool", "help"},
+			want: []string{
+				"Usage: tool <cmd> [args...]",
+				"",
+				"Commands:",
+				"  status                      Shows the status",
+				"  help        [<command>...]  Displays help information about the program or a specific command",
+				"  completion  <shell>         Prints the completion script of the program for the given shell",
+				"",
+				"Maintenance:",
+				"  gc                          Cleans up",
+				"  fsck                        Checks",
+				"",
+				"Remotes:",
+				"  remote      <cmd>           Manages the remotes",
+				"  remote add  <name>          Adds a remote",
+				"  fetch                       Fetches",
+			},
+		},
+		{
+			name: "command",
+			args: []string{"tool", "help", "remote"},
+			want: []string{
+				"remote — Manages the remotes",
+				"",
+				"Usage:",
+				"  tool remote <cmd>",
+				"",
+				"Commands:",
+				"  remote add  <name>  Adds a remote",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			p := new_test_help_program(&out)
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run(tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := strings.Join(tt.want, "\n") + "\n"
+
+			if out.String() != want {
+				t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+			}
+		})
+	}
+}
+
+func TestHelpHiddenCommands(t *testing.T) {
+	var out bytes.Buffer
+
+	p := new_test_help_program(&out)
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{""}, []string{"completion", "fetch", "fsck", "gc", "help", "remote", "status"}},
+		{[]string{"se"}, nil},
+		{[]string{"remote", ""}, []string{"add"}},
+	}
+
+	for _, tt := range tests {
+		got := p.Complete(tt.words)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+
+	// Hidden commands are left out of the listings but still run and have a page.
+	err = p.Run([]string{"tool", "help", "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(out.String(), "secret — Hidden\n") {
+		t.Errorf("unexpected help of a hidden command %q", out.String())
+	}
+}
@@ -0,0 +1,177 @@
+package simple
+
+import (
+	"errors"
+	"slices"
+)
+
+// FinallyFn is a hook that runs after a command, whether it succeeded or not.
+//
+// Parameters:
+//   - p: The program that runs the command.
+//   - err: The error of the command and of its other hooks. Nil if they succeeded.
+//
+// Returns:
+//   - error: An error if the hook failed. It is joined to err.
+type FinallyFn func(p *Program, err error) error
+
+// Middleware wraps the run function of a command with cross-cutting behaviour.
+// (i.e., timing, authentication checks, audit logging)
+//
+// Parameters:
+//   - next: The run function to wrap. Never nil.
+//
+// Returns:
+//   - CmdRunFn: The wrapped run function. Must call next to run the command.
+type Middleware func(next CmdRunFn) CmdRunFn
+
+// Hooks is the set of hooks that surround the run function of a command. Both
+// Program and Command embed it; the hooks of the program and of all the ancestors
+// of a command apply to it as well.
+//
+// When a command runs:
+//  1. PreRun hooks run, outermost first (program, then ancestors, then command).
+//  2. The run function runs, wrapped by the middlewares; outermost middlewares
+//     wrap innermost ones.
+//  3. PostRun hooks run, innermost first.
+//  4. Finally hooks run, innermost first.
+//
+// A failing step skips the steps 1 to 3 that follow it; Finally hooks always run.
+type Hooks struct {
+	// PreRun is the list of hooks that run before the command.
+	PreRun []CmdRunFn
+
+	// PostRun is the list of hooks that run after the command succeeded.
+	PostRun []CmdRunFn
+
+	// Finally is the list of hooks that run after the command, whether it succeeded
+	// or not.
+	Finally []FinallyFn
+
+	// Middlewares is the list of middlewares that wrap the run function of the
+	// command. The first middleware is the outermost one.
+	Middlewares []Middleware
+}
+
+// Fix implements the errors.Fixer interface.
+func (h *Hooks) Fix() error {
+	if h == nil {
+		return nil
+	}
+
+	h.PreRun = slices.DeleteFunc(h.PreRun, func(fn CmdRunFn) bool { return fn == nil })
+	h.PostRun = slices.DeleteFunc(h.PostRun, func(fn CmdRunFn) bool { return fn == nil })
+	h.Finally = slices.DeleteFunc(h.Finally, func(fn FinallyFn) bool { return fn == nil })
+	h.Middlewares = slices.DeleteFunc(h.Middlewares, func(mw Middleware) bool { return mw == nil })
+
+	return nil
+}
+
+// Use appends middlewares to the hooks.
+//
+// Parameters:
+//   - middlewares: The middlewares to append. Nil middlewares are ignored.
+func (h *Hooks) Use(middlewares ...Middleware) {
+	if h == nil {
+		return
+	}
+
+	for _, mw := range middlewares {
+		if mw != nil {
+			h.Middlewares = append(h.Middlewares, mw)
+		}
+	}
+}
+
+// hook_chain returns the hooks that apply to the given command, outermost first;
+// that is, the hooks of the program followed by those of the command's ancestors
+// and of the command itself.
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//
+// Returns:
+//   - []*Hooks: The hooks, outermost first.
+func (p *Program) hook_chain(cmd *Command) []*Hooks {
+	chain := []*Hooks{&cmd.Hooks}
+
+	for parent := cmd.parent; parent != nil; parent = parent.parent {
+		chain = append(chain, &parent.Hooks)
+	}
+
+	chain = append(chain, &p.Hooks)
+
+	slices.Reverse(chain)
+
+	return chain
+}
+
+// run_command runs the given command surrounded by its hooks and middlewares.
+//
+// Parameters:
+//   - cmd: The command to run. Assumed to not be nil.
+//   - args: The positional arguments of the command.
+//
+// Returns:
+//   - error: The joined errors of the command and of its hooks.
+func (p *Program) run_command(cmd *Command, args []string) error {
+	chain := p.hook_chain(cmd)
+
+	run_fn := cmd.run_fn()
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		mws := chain[i].Middlewares
+
+		for j := len(mws) - 1; j >= 0; j-- {
+			run_fn = mws[j](run_fn)
+		}
+	}
+
+	err := run_hooks(p, args, chain, run_fn)
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, fn := range chain[i].Finally {
+			err = errors.Join(err, fn(p, err))
+		}
+	}
+
+	return err
+}
+
+// run_hooks runs the pre-run hooks, the run function and the post-run hooks of the
+// given chain, stopping at the first error.
+//
+// Parameters:
+//   - p: The program that runs the command.
+//   - args: The positional arguments of the command.
+//   - chain: The hooks, outermost first.
+//   - run_fn: The wrapped run function.
+//
+// Returns:
+//   - error: The first error that occurred.
+func run_hooks(p *Program, args []string, chain []*Hooks, run_fn CmdRunFn) error {
+	for _, hooks := range chain {
+		for _, fn := range hooks.PreRun {
+			err := fn(p, args)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := run_fn(p, args)
+	if err != nil {
+		return err
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, fn := range chain[i].PostRun {
+			err := fn(p, args)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// new_test_hooks_program creates the program used by the tests of the hooks. Every
+// hook of the program, of the "remote" command and of its "add" sub-command records
+// its name in log, and the steps named in fail return an error.
+func new_test_hooks_program(log *[]string, fail []string) *Program {
+	step := func(name string) error {
+		*log = append(*log, name)
+
+		if slices.Contains(fail, name) {
+			return errors.New(name + " failed")
+		}
+
+		return nil
+	}
+
+	pre := func(name string) CmdRunFn {
+		return func(_ *Program, _ []string) error { return step("pre:" + name) }
+	}
+
+	post := func(name string) CmdRunFn {
+		return func(_ *Program, _ []string) error { return step("post:" + name) }
+	}
+
+	finally := func(name string) FinallyFn {
+		return func(_ *Program, err error) error {
+			if err != nil {
+				name += " (failed)"
+			}
+
+			return step("finally:" + name)
+		}
+	}
+
+	middleware := func(name string) Middleware {
+		return func(next CmdRunFn) CmdRunFn {
+			return func(p *Program, args []string) error {
+				*log = append(*log, name+" >")
+				err := next(p, args)
+				*log = append(*log, "< "+name)
+
+				return err
+			}
+		}
+	}
+
+	hooks := func(name string) Hooks {
+		return Hooks{
+			PreRun:      []CmdRunFn{pre(name)},
+			PostRun:     []CmdRunFn{post(name)},
+			Finally:     []FinallyFn{finally(name)},
+			Middlewares: []Middleware{middleware("mw:" + name)},
+		}
+	}
+
+	add := &Command{
+		Name:  "add",
+		Hooks: hooks("add"),
+		RunFn: func(_ *Program, _ []string) error { return step("run") },
+	}
+
+	remote := &Command{Name: "remote", Hooks: hooks("remote")}
+	remote.AddCommands(add)
+
+	p := &Program{
+		Name:  "tool",
+		Out:   &bytes.Buffer{},
+		Err:   &bytes.Buffer{},
+		Hooks: hooks("program"),
+	}
+
+	p.Use(middleware("mw:program2"))
+	p.AddCommands(remote)
+
+	return p
+}
+
+func TestRunHooks(t *testing.T) {
+	tests := []struct {
+		name string
+		fail []string
+		log  []string
+		err  string
+	}{
+		{
+			name: "success",
+			log: []string{
+				"pre:program", "pre:remote", "pre:add",
+				"mw:program >", "mw:program2 >", "mw:remote >", "mw:add >",
+				"run",
+				"< mw:add", "< mw:remote", "< mw:program2", "< mw:program",
+				"post:add", "post:remote", "post:program",
+				"finally:add", "finally:remote", "finally:program",
+			},
+		},
+		{
+			name: "pre-run fails",
+			fail: []string{"pre:remote"},
+			log: []string{
+				"pre:program", "pre:remote",
+				"finally:add (failed)", "finally:remote (failed)", "finally:program (failed)",
+			},
+			err: "pre:remote failed",
+		},
+		{
+			name: "run fails",
+			fail: []string{"run"},
+			log: []string{
+				"pre:program", "pre:remote", "pre:add",
+				"mw:program >", "mw:program2 >", "mw:remote >", "mw:add >",
+				"run",
+				"< mw:add", "< mw:remote", "< mw:program2", "< mw:program",
+				"finally:add (failed)", "finally:remote (failed)", "finally:program (failed)",
+			},
+			err: "run failed",
+		},
+		{
+			name: "post-run fails",
+			fail: []string{"post:add"},
+			log: []string{
+				"pre:program", "pre:remote", "pre:add",
+				"mw:program >", "mw:program2 >", "mw:remote >", "mw:add >",
+				"run",
+				"< mw:add", "< mw:remote", "< mw:program2", "< mw:program",
+				"post:add",
+				"finally:add (failed)", "finally:remote (failed)", "finally:program (failed)",
+			},
+			err: "post:add failed",
+		},
+		{
+			name: "finally fails",
+			fail: []string{"finally:add"},
+			log: []string{
+				"pre:program", "pre:remote", "pre:add",
+				"mw:program >", "mw:program2 >", "mw:remote >", "mw:add >",
+				"run",
+				"< mw:add", "< mw:remote", "< mw:program2", "< mw:program",
+				"post:add", "post:remote", "post:program",
+				"finally:add", "finally:remote (failed)", "finally:program (failed)",
+			},
+			err: "finally:add failed",
+		},
+		{
+			name: "errors are joined",
+			fail: []string{"pre:remote", "finally:remote (failed)"},
+			log: []string{
+				"pre:program", "pre:remote",
+				"finally:add (failed)", "finally:remote (failed)", "finally:program (failed)",
+			},
+			err: "pre:remote failed\nfinally:remote (failed) failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+
+			p := new_test_hooks_program(&log, tt.fail)
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run([]string{"tool", "remote", "add"})
+
+			var got, want string
+
+			if err != nil {
+				got = err.Error()
+			}
+
+			if tt.err != "" {
+				want = `command "remote add" failed: ` + tt.err
+			}
+
+			if got != want {
+				t.Errorf("error = %q, want %q", got, want)
+			}
+
+			if strings.Join(log, ", ") != strings.Join(tt.log, ", ") {
+				t.Errorf("log:\n  %s\nwant:\n  %s", strings.Join(log, ", "), strings.Join(tt.log, ", "))
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package simple
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// lookup_command looks up the command with the given name or alias in the given
+// table.
+//
+// Parameters:
+//   - table: The table of commands.
+//   - name: The name or alias of the command.
+//   - allow_prefix: Whether an unambiguous prefix of a name or alias resolves to the
+//     command.
+//
+// Returns:
+//   - *Command: The command. Nil if not found.
+//   - error: An *ErrAmbiguousCommand if the prefix matches more than one command.
+func lookup_command(table map[string]*Command, name string, allow_prefix bool) (*Command, error) {
+	cmd, ok := table[name]
+	if ok {
+		return cmd, nil
+	}
+
+	for _, cmd := range table {
+		if slices.Contains(cmd.Aliases, name) {
+			return cmd, nil
+		}
+	}
+
+	if !allow_prefix || name == "" {
+		return nil, nil
+	}
+
+	var matches []*Command
+
+	for _, cmd := range table {
+		if cmd.Hidden {
+			continue
+		}
+
+		ok := strings.HasPrefix(cmd.Name, name)
+
+		for i := 0; i < len(cmd.Aliases) && !ok; i++ {
+			ok = strings.HasPrefix(cmd.Aliases[i], name)
+		}
+
+		if ok {
+			matches = append(matches, cmd)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+
+	candidates := make([]string, 0, len(matches))
+
+	for _, cmd := range matches {
+		candidates = append(candidates, cmd.Name)
+	}
+
+	slices.Sort(candidates)
+
+	return nil, NewErrAmbiguousCommand(name, candidates)
+}
+
+// has_name checks whether a command of the given table is named or aliased with the
+// given name.
+//
+// Parameters:
+//   - table: The table of commands.
+//   - name: The name.
+//
+// Returns:
+//   - bool: True if the name is taken, false otherwise.
+func has_name(table map[string]*Command, name string) bool {
+	cmd, _ := lookup_command(table, name, false)
+	return cmd != nil
+}
+
+// check_collisions checks that no name or alias is shared by two commands of the
+// given table.
+//
+// Parameters:
+//   - table: The table of commands.
+//
+// Returns:
+//   - error: An error if a name or alias is shared by two commands.
+func check_collisions(table map[string]*Command) error {
+	owners := make(map[string]*Command, len(table))
+
+	keys := make([]string, 0, len(table))
+
+	for k := range table {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		cmd := table[k]
+
+		names := append([]string{cmd.Name}, cmd.Aliases...)
+
+		for _, name := range names {
+			owner, ok := owners[name]
+			if ok && owner != cmd {
+				return fmt.Errorf("%q is used by both command %q and command %q", name, owner.FullName(), cmd.FullName())
+			}
+
+			owners[name] = cmd
+		}
+	}
+
+	return nil
+}
+
+// add_command adds the given command to the given table. Commands keep the position
+// of their first registration; re-adding a name replaces the command in place.
+//
+// Parameters:
+//   - table: The table of commands. Assumed to not be nil.
+//   - order: The names of the commands of the table, in order of registration.
+//   - cmd: The command to add. Assumed to not be nil.
+//
+// Returns:
+//   - []string: The updated order of registration.
+func add_command(table map[string]*Command, order []string, cmd *Command) []string {
+	_, ok := table[cmd.Name]
+
+	table[cmd.Name] = cmd
+
+	if !ok {
+		order = append(order, cmd.Name)
+	}
+
+	return order
+}
+
+// ordered_commands returns the commands of the given table in order of registration.
+//
+// Parameters:
+//   - table: The table of commands.
+//   - order: The names of the commands of the table, in order of registration.
+//
+// Returns:
+//   - []*Command: The commands, in order of registration.
+func ordered_commands(table map[string]*Command, order []string) []*Command {
+	commands := make([]*Command, 0, len(order))
+
+	for _, name := range order {
+		cmd, ok := table[name]
+		if ok {
+			commands = append(commands, cmd)
+		}
+	}
+
+	return commands
+}
@@ -0,0 +1,195 @@
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// new_test_lookup_table creates the table of commands used by the tests of this
+// file.
+func new_test_lookup_table() map[string]*Command {
+	table := make(map[string]*Command)
+
+	for _, cmd := range []*Command{
+		{Name: "status", Aliases: []string{"st"}},
+		{Name: "stash"},
+		{Name: "start"},
+		{Name: "remove", Aliases: []string{"rm", "delete"}},
+		{Name: "secret", Aliases: []string{"hush"}, Hidden: true},
+	} {
+		table[cmd.Name] = cmd
+	}
+
+	return table
+}
+
+func TestLookupCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		prefix bool
+		want   string
+	}{
+		{"name", "stash", false, "stash"},
+		{"alias", "rm", false, "remove"},
+		{"alias beats prefix", "st", true, "status"},
+		{"prefix off", "rem", false, ""},
+		{"prefix", "rem", true, "remove"},
+		{"prefix of an alias", "del", true, "remove"},
+		{"hidden by name", "secret", false, "secret"},
+		{"hidden by alias", "hush", false, "secret"},
+		{"hidden is skipped for prefixes", "sec", true, ""},
+		{"hidden alias is skipped for prefixes", "hu", true, ""},
+		{"empty name", "", true, ""},
+		{"unknown", "deploy", true, ""},
+	}
+
+	for _, tt := range tests {
+		cmd, err := lookup_command(new_test_lookup_table(), tt.input, tt.prefix)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		var got string
+
+		if cmd != nil {
+			got = cmd.Name
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: lookup_command(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLookupCommandAmbiguous(t *testing.T) {
+	const want = `command "sta" is ambiguous; it could be "start", "stash", "status"`
+
+	// The candidates come from a map; the message must not depend on its order.
+	for i := 0; i < 50; i++ {
+		_, err := lookup_command(new_test_lookup_table(), "sta", true)
+
+		var ambiguous *ErrAmbiguousCommand
+
+		if !errors.As(err, &ambiguous) {
+			t.Fatalf("error = %v, want an *ErrAmbiguousCommand", err)
+		}
+
+		if err.Error() != want {
+			t.Fatalf("error = %q, want %q", err.Error(), want)
+		}
+	}
+}
+
+func TestCheckCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []*Command
+		want     string
+	}{
+		{
+			name:     "no collision",
+			commands: []*Command{{Name: "add", Aliases: []string{"a"}}, {Name: "remove", Aliases: []string{"rm"}}},
+		},
+		{
+			name:     "name and alias",
+			commands: []*Command{{Name: "add"}, {Name: "remove", Aliases: []string{"add"}}},
+			want:     `"add" is used by both command "add" and command "remove"`,
+		},
+		{
+			name:     "two aliases",
+			commands: []*Command{{Name: "zap", Aliases: []string{"x"}}, {Name: "yank", Aliases: []string{"x"}}, {Name: "move", Aliases: []string{"x"}}},
+			want:     `"x" is used by both command "move" and command "yank"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The table is a map; the message must not depend on its order.
+			for i := 0; i < 50; i++ {
+				table := make(map[string]*Command)
+
+				for _, cmd := range tt.commands {
+					table[cmd.Name] = cmd
+				}
+
+				err := check_collisions(table)
+
+				var got string
+
+				if err != nil {
+					got = err.Error()
+				}
+
+				if got != tt.want {
+					t.Fatalf("error = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProgramFixCollisions(t *testing.T) {
+	p := &Program{Name: "tool", Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	p.AddCommands(&Command{Name: "status"}, &Command{Name: "stash", Aliases: []string{"status"}})
+
+	err := p.Fix()
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRunPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix bool
+		args   []string
+		want   string
+		code   int
+	}{
+		{"unique prefix", true, []string{"tool", "stas"}, "stash", ExitSuccess},
+		{"alias", false, []string{"tool", "rm"}, "remove", ExitSuccess},
+		{"prefix off", false, []string{"tool", "stas"}, "", ExitUsage},
+		{"ambiguous", true, []string{"tool", "sta"}, "", ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+
+			p := &Program{
+				Name:        "tool",
+				Out:         &bytes.Buffer{},
+				Err:         &bytes.Buffer{},
+				AllowPrefix: tt.prefix,
+			}
+
+			for _, cmd := range new_test_lookup_table() {
+				cmd.RunFn = func(p *Program, _ []string) error {
+					got = p.CurrentCommand().Name
+					return nil
+				}
+
+				p.AddCommands(cmd)
+			}
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run(tt.args)
+
+			if got != tt.want {
+				t.Errorf("command = %q, want %q", got, tt.want)
+			}
+
+			code := p.ExitCode(err)
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d (error: %v)", code, tt.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package simple
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ManOptions are the options of the man page generator.
+type ManOptions struct {
+	// Section is the section of the manual. If empty, "1" is used.
+	Section string
+
+	// Date is the date shown in the footer of the pages. If zero, the current date
+	// is used.
+	Date time.Time
+
+	// Source is the source shown in the footer of the pages. If empty, the name and
+	// version of the program are used.
+	Source string
+
+	// Manual is the title of the manual shown in the header of the pages. If empty,
+	// "<name> Manual" is used.
+	Manual string
+}
+
+// fix is a helper method that fills the missing options of the man page generator.
+//
+// Parameters:
+//   - p: The program to document.
+//
+// Returns:
+//   - ManOptions: The options with every field set.
+func (opts *ManOptions) fix(p Program) ManOptions {
+	var res ManOptions
+
+	if opts != nil {
+		res = *opts
+	}
+
+	if res.Section == "" {
+		res.Section = "1"
+	}
+
+	if res.Date.IsZero() {
+		res.Date = time.Now()
+	}
+
+	if res.Source == "" {
+		res.Source = strings.TrimSpace(p.Name + " " + p.Version)
+	}
+
+	if res.Manual == "" {
+		res.Manual = p.Name + " Manual"
+	}
+
+	return res
+}
+
+// NewManCommand creates a hidden command that generates the man pages of the
+// program into the given directory. It is not registered by default; add it with
+// Program.AddCommands.
+//
+// Returns:
+//   - *Command: The command. Never returns nil.
+func NewManCommand() *Command {
+	return &Command{
+		Name:  "gen-man",
+		Brief: "Generates the man pages of the program",
+		RunFn: func(p *Program, args []string) error {
+			return p.GenManTree(args[0], nil)
+		},
+		Argument: NewArgument(PathArg("dir")),
+		Hidden:   true,
+	}
+}
+
+// GenManTree writes the man page of the program and one man page per visible
+// command into the given directory, creating it if needed. The pages are named
+// "<name>.<section>" and "<name>-<command path>.<section>".
+//
+// Parameters:
+//   - dir: The directory to write the pages to.
+//   - opts: The options of the generator. If nil, the defaults are used.
+//
+// Returns:
+//   - error: An error if a page could not be written.
+func (p Program) GenManTree(dir string, opts *ManOptions) error {
+	o := opts.fix(p)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = write_file(filepath.Join(dir, p.Name+"."+o.Section), func(w io.Writer) error {
+		return p.GenMan(w, &o)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range visible_commands(p.top_commands(), true) {
+		path := filepath.Join(dir, man_page_name(p, cmd)+"."+o.Section)
+
+		err := write_file(path, func(w io.Writer) error {
+			return p.GenCommandMan(w, cmd, &o)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GenMan writes the man page of the program.
+//
+// Parameters:
+//   - w: The writer to write the page to.
+//   - opts: The options of the generator. If nil, the defaults are used.
+//
+// Returns:
+//   - error: An error if the page could not be written.
+func (p Program) GenMan(w io.Writer, opts *ManOptions) error {
+	o := opts.fix(p)
+
+	commands := visible_commands(p.top_commands(), true)
+
+	var builder strings.Builder
+
+	write_man_header(&builder, strings.ToUpper(p.Name), o)
+
+	builder.WriteString(".SH NAME\n")
+	builder.WriteString(roff_escape(p.Name))
+	builder.WriteRune('\n')
+
+	builder.WriteString(".SH SYNOPSIS\n")
+	builder.WriteString(".B ")
+	builder.WriteString(roff_escape(p.Name))
+	builder.WriteRune('\n')
+	builder.WriteString(roff_escape(p.usage()))
+	builder.WriteRune('\n')
+
+	if len(commands) > 0 {
+		builder.WriteString(".SH COMMANDS\n")
+
+		for _, cmd := range commands {
+			builder.WriteString(".TP\n.B ")
+			builder.WriteString(roff_escape(cmd.Usage()))
+			builder.WriteRune('\n')
+			builder.WriteString(roff_escape(cmd.Brief))
+			builder.WriteRune('\n')
+		}
+
+		builder.WriteString(".SH SEE ALSO\n")
+
+		refs := make([]string, 0, len(commands))
+
+		for _, cmd := range commands {
+			refs = append(refs, ".BR "+roff_escape(man_page_name(p, cmd))+" ("+o.Section+")")
+		}
+
+		builder.WriteString(strings.Join(refs, ",\n"))
+		builder.WriteRune('\n')
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// GenCommandMan writes the man page of the given command.
+//
+// Parameters:
+//   - w: The writer to write the page to.
+//   - cmd: The command to document.
+//   - opts: The options of the generator. If nil, the defaults are used.
+//
+// Returns:
+//   - error: An error if the command is nil or the page could not be written.
+func (p Program) GenCommandMan(w io.Writer, cmd *Command, opts *ManOptions) error {
+	if cmd == nil {
+		return errors.New("command cannot be nil")
+	}
+
+	o := opts.fix(p)
+
+	page_name := man_page_name(p, cmd)
+
+	var builder strings.Builder
+
+	write_man_header(&builder, strings.ToUpper(page_name), o)
+
+	builder.WriteString(".SH NAME\n")
+	builder.WriteString(roff_escape(page_name))
+
+	if cmd.Brief != "" {
+		builder.WriteString(" \\- ")
+		builder.WriteString(roff_escape(cmd.Brief))
+	}
+
+	builder.WriteRune('\n')
+
+	builder.WriteString(".SH SYNOPSIS\n")
+	builder.WriteString(".B ")
+	builder.WriteString(roff_escape(p.Name + " " + cmd.FullName()))
+	builder.WriteRune('\n')
+
+	usage := strings.TrimSpace(strings.TrimPrefix(cmd.Usage(), cmd.FullName()))
+	if usage != "" {
+		builder.WriteString(roff_escape(usage))
+		builder.WriteRune('\n')
+	}
+
+	if len(cmd.Aliases) > 0 {
+		builder.WriteString(".SH ALIASES\n")
+		builder.WriteString(roff_escape(strings.Join(cmd.Aliases, ", ")))
+		builder.WriteRune('\n')
+	}
+
+	if cmd.HasFlags() {
+		builder.WriteString(".SH OPTIONS\n")
+
+		for flag := range cmd.flag_set.Flags() {
+			builder.WriteString(".TP\n.B ")
+			builder.WriteString(roff_escape(flag.String()))
+			builder.WriteRune('\n')
+			builder.WriteString(roff_escape(flag.Brief()))
+			builder.WriteRune('\n')
+		}
+	}
+
+	env_rows := p.env_rows(cmd)
+
+	if len(env_rows) > 0 {
+		builder.WriteString(".SH ENVIRONMENT\n")
+
+		for _, row := range env_rows {
+			builder.WriteString(".TP\n.B ")
+			builder.WriteString(roff_escape(row[0]))
+			builder.WriteRune('\n')
+			builder.WriteString(roff_escape("Fallback of " + row[1]))
+			builder.WriteRune('\n')
+		}
+	}
+
+	subs := visible_commands(ordered_commands(cmd.sub_commands, cmd.sub_order), false)
+
+	if len(subs) > 0 {
+		builder.WriteString(".SH COMMANDS\n")
+
+		for _, sub := range subs {
+			builder.WriteString(".TP\n.B ")
+			builder.WriteString(roff_escape(sub.Usage()))
+			builder.WriteRune('\n')
+			builder.WriteString(roff_escape(sub.Brief))
+			builder.WriteRune('\n')
+		}
+	}
+
+	if len(cmd.Examples) > 0 {
+		builder.WriteString(".SH EXAMPLES\n")
+
+		for _, example := range cmd.Examples {
+			builder.WriteString(".PP\n")
+			builder.WriteString(roff_escape(p.Name + " " + example))
+			builder.WriteRune('\n')
+		}
+	}
+
+	builder.WriteString(".SH SEE ALSO\n")
+
+	parent := p.Name
+	if cmd.parent != nil {
+		parent = man_page_name(p, cmd.parent)
+	}
+
+	builder.WriteString(".BR ")
+	builder.WriteString(roff_escape(parent))
+	builder.WriteString(" (")
+	builder.WriteString(o.Section)
+	builder.WriteString(")\n")
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// top_commands returns the top-level commands of the program.
+//
+// Returns:
+//   - []*Command: The top-level commands, in order of registration.
+func (p Program) top_commands() []*Command {
+	return ordered_commands(p.command_table, p.command_order)
+}
+
+// man_page_name returns the name of the man page of the given command. (i.e.,
+// "tool-remote-add")
+//
+// Parameters:
+//   - p: The program.
+//   - cmd: The command.
+//
+// Returns:
+//   - string: The name of the page.
+func man_page_name(p Program, cmd *Command) string {
+	return p.Name + "-" + strings.ReplaceAll(cmd.FullName(), " ", "-")
+}
+
+// write_man_header writes the .TH line of a man page.
+//
+// Parameters:
+//   - builder: The builder to write to.
+//   - title: The title of the page.
+//   - o: The options of the generator.
+func write_man_header(builder *strings.Builder, title string, o ManOptions) {
+	fields := []string{
+		roff_escape(title),
+		o.Section,
+		o.Date.Format("Jan 2006"),
+		roff_escape(o.Source),
+		roff_escape(o.Manual),
+	}
+
+	builder.WriteString(".TH")
+
+	for _, field := range fields {
+		builder.WriteString(" \"")
+		builder.WriteString(strings.ReplaceAll(field, "\"", "\\(dq"))
+		builder.WriteRune('"')
+	}
+
+	builder.WriteRune('\n')
+}
+
+// roff_escape escapes the given text so that roff prints it verbatim.
+//
+// Parameters:
+//   - text: The text to escape.
+//
+// Returns:
+//   - string: The escaped text.
+func roff_escape(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\e")
+	text = strings.ReplaceAll(text, "-", "\\-")
+
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = "\\&" + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// write_file creates the file at the given path and writes to it with the given
+// function.
+//
+// Parameters:
+//   - path: The path of the file.
+//   - fn: The function that writes the content of the file.
+//
+// Returns:
+//   - error: An error if the file could not be written.
+func write_file(path string, fn func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = fn(f)
+
+	close_err := f.Close()
+	if err == nil {
+		err = close_err
+	}
+
+	return err
+}
@@ -0,0 +1,127 @@
+package simple_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PlayerR9/LyneCml/simple"
+	"github.com/PlayerR9/LyneCml/simple/simpletest"
+)
+
+// new_test_man_program creates the program used by the tests of the man pages. Its
+// hidden "man" command writes the page of the program, or of the command whose path
+// is given, to the output.
+func new_test_man_program() *simple.Program {
+	add := &simple.Command{
+		Name:     "add",
+		Aliases:  []string{"new"},
+		Brief:    "Adds a remote named with a back\\slash.\n.dots and\n'quotes start these lines",
+		Argument: simple.NewArgument(simple.StringArg("name")),
+		Examples: []string{"remote add --dry-run origin"},
+	}
+
+	add.Flags().Bool("dry-run", false, "Only prints what would be done", simple.WithShortName('n'))
+
+	remote := &simple.Command{Name: "remote", Brief: "Manages the remotes"}
+	remote.AddCommands(add, &simple.Command{Name: "prune", Hidden: true})
+
+	opts := &simple.ManOptions{Date: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)}
+
+	man := &simple.Command{
+		Name:     "man",
+		Argument: simple.AtMostNArgs(simple.StringArg("command"), 2),
+		Hidden:   true,
+		RunFn: func(p *simple.Program, args []string) error {
+			if len(args) == 0 {
+				return p.GenMan(p, opts)
+			}
+
+			cmd, _ := p.RetrieveCommand(args[0])
+
+			for _, name := range args[1:] {
+				cmd, _ = cmd.RetrieveCommand(name)
+			}
+
+			return p.GenCommandMan(p, cmd, opts)
+		},
+	}
+
+	p := &simple.Program{
+		Name:    "tool",
+		Version: "1.0.0",
+	}
+
+	p.AddCommands(remote, man, simple.NewManCommand())
+
+	return p
+}
+
+func TestGenManGolden(t *testing.T) {
+	simpletest.RunCases(t, new_test_man_program(), []simpletest.Case{
+		{Name: "program", Options: simpletest.Options{Args: []string{"man"}}, Golden: "testdata/man/tool.1.golden"},
+		{Name: "command", Options: simpletest.Options{Args: []string{"man", "remote"}}, Golden: "testdata/man/tool-remote.1.golden"},
+		{Name: "sub-command", Options: simpletest.Options{Args: []string{"man", "remote", "add"}}, Golden: "testdata/man/tool-remote-add.1.golden"},
+	})
+}
+
+func TestGenCommandManEscapes(t *testing.T) {
+	res, err := simpletest.Run(new_test_man_program(), simpletest.Options{Args: []string{"man", "remote", "add"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"dash", `\-\-dry\-run`},
+		{"backslash", `back\eslash`},
+		{"leading dot", "\n\\&.dots and\n"},
+		{"leading quote", "\n\\&'quotes start these lines\n"},
+	}
+
+	for _, tt := range tests {
+		if !strings.Contains(res.Stdout, tt.want) {
+			t.Errorf("%s: page does not contain %q:\n%s", tt.name, tt.want, res.Stdout)
+		}
+	}
+}
+
+func TestGenManTree(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "man")
+
+	res, err := simpletest.Run(new_test_man_program(), simpletest.Options{Args: []string{"gen-man", dir}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if res.Err != nil {
+		t.Fatalf("unexpected error: %v", res.Err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+
+	want := []string{
+		"tool-completion.1",
+		"tool-help.1",
+		"tool-remote-add.1",
+		"tool-remote.1",
+		"tool-version.1",
+		"tool.1",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("pages = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,317 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MarkdownIndex is the name of the index page of the Markdown documentation.
+	MarkdownIndex string = "index.md"
+)
+
+// GenMarkdownTree writes the Markdown documentation of the program into the given
+// directory, creating it if needed: an index page listing every visible command and
+// one page per visible command. Pages link to each other with relative links.
+//
+// Nothing is written if two pages would share a file; such as the page of a
+// top-level command named "index" and the index, or the pages of the commands
+// "remote_add" and "remote add".
+//
+// Parameters:
+//   - dir: The directory to write the pages to.
+//
+// Returns:
+//   - error: An error if two pages would share a file or a page could not be
+//     written.
+func (p Program) GenMarkdownTree(dir string) error {
+	commands := visible_commands(p.top_commands(), true)
+
+	err := check_markdown_pages(commands)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = write_file(filepath.Join(dir, MarkdownIndex), p.GenMarkdown)
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range commands {
+		err := write_file(filepath.Join(dir, markdown_page_name(cmd)), func(w io.Writer) error {
+			return p.GenCommandMarkdown(w, cmd)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GenMarkdown writes the index page of the Markdown documentation.
+//
+// Parameters:
+//   - w: The writer to write the page to.
+//
+// Returns:
+//   - error: An error if the page could not be written.
+func (p Program) GenMarkdown(w io.Writer) error {
+	var builder strings.Builder
+
+	builder.WriteString("# ")
+	builder.WriteString(p.Name)
+	builder.WriteString("\n\n")
+
+	if p.Version != "" {
+		builder.WriteString("Version: ")
+		builder.WriteString(p.Version)
+		builder.WriteString("\n\n")
+	}
+
+	builder.WriteString("## Usage\n\n")
+	write_code_block(&builder, p.Name+" "+p.usage())
+
+	commands := visible_commands(p.top_commands(), true)
+
+	if len(commands) > 0 {
+		builder.WriteString("\n## Commands\n\n")
+		builder.WriteString("| Command | Usage | Description |\n")
+		builder.WriteString("| --- | --- | --- |\n")
+
+		for _, cmd := range commands {
+			builder.WriteString("| ")
+			builder.WriteString(markdown_link(cmd.FullName(), markdown_page_name(cmd)))
+			builder.WriteString(" | ")
+			builder.WriteString(markdown_code(cmd.ArgumentUsage()))
+			builder.WriteString(" | ")
+			builder.WriteString(markdown_cell(cmd.Brief))
+			builder.WriteString(" |\n")
+		}
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// GenCommandMarkdown writes the Markdown page of the given command.
+//
+// Parameters:
+//   - w: The writer to write the page to.
+//   - cmd: The command to document.
+//
+// Returns:
+//   - error: An error if the command is nil or the page could not be written.
+func (p Program) GenCommandMarkdown(w io.Writer, cmd *Command) error {
+	if cmd == nil {
+		return errors.New("command cannot be nil")
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("# ")
+	builder.WriteString(p.Name)
+	builder.WriteRune(' ')
+	builder.WriteString(cmd.FullName())
+	builder.WriteString("\n\n")
+
+	if cmd.Brief != "" {
+		builder.WriteString(cmd.Brief)
+		builder.WriteString("\n\n")
+	}
+
+	builder.WriteString("## Usage\n\n")
+	write_code_block(&builder, p.Name+" "+cmd.Usage())
+
+	if len(cmd.Aliases) > 0 {
+		aliases := make([]string, 0, len(cmd.Aliases))
+
+		for _, alias := range cmd.Aliases {
+			aliases = append(aliases, markdown_code(alias))
+		}
+
+		builder.WriteString("\n## Aliases\n\n")
+		builder.WriteString(strings.Join(aliases, ", "))
+		builder.WriteString("\n")
+	}
+
+	if cmd.HasFlags() {
+		builder.WriteString("\n## Flags\n\n")
+		builder.WriteString("| Flag | Description |\n")
+		builder.WriteString("| --- | --- |\n")
+
+		for flag := range cmd.flag_set.Flags() {
+			builder.WriteString("| ")
+			builder.WriteString(markdown_code(flag.String()))
+			builder.WriteString(" | ")
+			builder.WriteString(markdown_cell(flag.Brief()))
+			builder.WriteString(" |\n")
+		}
+	}
+
+	env_rows := p.env_rows(cmd)
+
+	if len(env_rows) > 0 {
+		builder.WriteString("\n## Environment\n\n")
+		builder.WriteString("| Variable | Fallback of |\n")
+		builder.WriteString("| --- | --- |\n")
+
+		for _, row := range env_rows {
+			builder.WriteString("| ")
+			builder.WriteString(markdown_code(row[0]))
+			builder.WriteString(" | ")
+			builder.WriteString(markdown_code(row[1]))
+			builder.WriteString(" |\n")
+		}
+	}
+
+	subs := visible_commands(ordered_commands(cmd.sub_commands, cmd.sub_order), false)
+
+	if len(subs) > 0 {
+		builder.WriteString("\n## Commands\n\n")
+		builder.WriteString("| Command | Usage | Description |\n")
+		builder.WriteString("| --- | --- | --- |\n")
+
+		for _, sub := range subs {
+			builder.WriteString("| ")
+			builder.WriteString(markdown_link(sub.FullName(), markdown_page_name(sub)))
+			builder.WriteString(" | ")
+			builder.WriteString(markdown_code(sub.ArgumentUsage()))
+			builder.WriteString(" | ")
+			builder.WriteString(markdown_cell(sub.Brief))
+			builder.WriteString(" |\n")
+		}
+	}
+
+	if len(cmd.Examples) > 0 {
+		builder.WriteString("\n## Examples\n\n")
+
+		lines := make([]string, 0, len(cmd.Examples))
+
+		for _, example := range cmd.Examples {
+			lines = append(lines, p.Name+" "+example)
+		}
+
+		write_code_block(&builder, strings.Join(lines, "\n"))
+	}
+
+	builder.WriteString("\n## See also\n\n")
+
+	if cmd.parent != nil {
+		builder.WriteString("- ")
+		builder.WriteString(markdown_link(p.Name+" "+cmd.parent.FullName(), markdown_page_name(cmd.parent)))
+
+		if cmd.parent.Brief != "" {
+			builder.WriteString(" — ")
+			builder.WriteString(cmd.parent.Brief)
+		}
+
+		builder.WriteRune('\n')
+	}
+
+	builder.WriteString("- ")
+	builder.WriteString(markdown_link(p.Name, MarkdownIndex))
+	builder.WriteRune('\n')
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// markdown_page_name returns the file name of the Markdown page of the given
+// command. (i.e., "remote_add.md")
+//
+// Parameters:
+//   - cmd: The command.
+//
+// Returns:
+//   - string: The file name of the page.
+func markdown_page_name(cmd *Command) string {
+	return strings.ReplaceAll(cmd.FullName(), " ", "_") + ".md"
+}
+
+// check_markdown_pages checks that no two of the given commands have their page
+// written to the same file, and that none of them is written to the index.
+//
+// Parameters:
+//   - commands: The documented commands.
+//
+// Returns:
+//   - error: An error if two pages would share a file.
+func check_markdown_pages(commands []*Command) error {
+	owners := map[string]string{
+		MarkdownIndex: "the index",
+	}
+
+	for _, cmd := range commands {
+		name := markdown_page_name(cmd)
+
+		owner, ok := owners[name]
+		if ok {
+			return fmt.Errorf("the page of command %q would overwrite %s: both are written to %q", cmd.FullName(), owner, name)
+		}
+
+		owners[name] = "the page of command " + strconv.Quote(cmd.FullName())
+	}
+
+	return nil
+}
+
+// markdown_link returns a Markdown link.
+//
+// Parameters:
+//   - text: The text of the link.
+//   - target: The target of the link.
+//
+// Returns:
+//   - string: The link.
+func markdown_link(text, target string) string {
+	return "[" + markdown_cell(text) + "](" + target + ")"
+}
+
+// markdown_code returns the given text as inline code suitable for a table cell.
+//
+// Parameters:
+//   - text: The text.
+//
+// Returns:
+//   - string: The inline code. Empty if text is empty.
+func markdown_code(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	return "`" + strings.ReplaceAll(text, "|", "\\|") + "`"
+}
+
+// markdown_cell escapes the given text so that it can be used in a table cell.
+//
+// Parameters:
+//   - text: The text.
+//
+// Returns:
+//   - string: The escaped text.
+func markdown_cell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// write_code_block writes the given text as a fenced code block.
+//
+// Parameters:
+//   - builder: The builder to write to.
+//   - text: The text.
+func write_code_block(builder *strings.Builder, text string) {
+	builder.WriteString("```\n")
+	builder.WriteString(text)
+	builder.WriteString("\n```\n")
+}
@@ -0,0 +1,138 @@
+package simple
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// markdown_link_pattern matches the targets of the links of a Markdown page.
+var markdown_link_pattern = regexp.MustCompile(`\]\(([^)]+)\)`)
+
+// new_test_markdown_program creates the program used by the tests of the Markdown
+// documentation, with the given extra top-level commands.
+func new_test_markdown_program(extra ...*Command) *Program {
+	remote := &Command{Name: "remote", Brief: "Manages the remotes"}
+	remote.AddCommands(
+		&Command{Name: "add", Brief: "Adds a remote"},
+		&Command{Name: "prune", Hidden: true},
+	)
+
+	p := &Program{Name: "tool", Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	p.AddCommands(remote)
+	p.AddCommands(extra...)
+
+	return p
+}
+
+// markdown_links returns the targets of the links of the given page, or fails the
+// test.
+func markdown_links(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var links []string
+
+	for _, match := range markdown_link_pattern.FindAllStringSubmatch(string(data), -1) {
+		links = append(links, match[1])
+	}
+
+	return links
+}
+
+func TestGenMarkdownTreeLinks(t *testing.T) {
+	dir := t.TempDir()
+
+	p := new_test_markdown_program()
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = p.GenMarkdownTree(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		page  string
+		links []string
+	}{
+		{MarkdownIndex, []string{"remote.md", "remote_add.md", "help.md", "completion.md"}},
+		{"remote.md", []string{"remote_add.md", MarkdownIndex}},
+		{"remote_add.md", []string{"remote.md", MarkdownIndex}},
+		{"help.md", []string{MarkdownIndex}},
+	}
+
+	for _, tt := range tests {
+		links := markdown_links(t, filepath.Join(dir, tt.page))
+
+		if !slices.Equal(links, tt.links) {
+			t.Errorf("links of %q = %q, want %q", tt.page, links, tt.links)
+		}
+
+		for _, link := range links {
+			_, err := os.Stat(filepath.Join(dir, link))
+			if err != nil {
+				t.Errorf("link %q of %q is broken: %v", link, tt.page, err)
+			}
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "remote_prune.md"))
+	if err == nil {
+		t.Error("the page of a hidden command was written")
+	}
+}
+
+func TestGenMarkdownTreeCollisions(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra *Command
+		fails bool
+	}{
+		{"index command", &Command{Name: "index"}, true},
+		{"flattened sub-command", &Command{Name: "remote_add"}, true},
+		{"hidden index command", &Command{Name: "index", Hidden: true}, false},
+		{"no collision", &Command{Name: "status"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "docs")
+
+			p := new_test_markdown_program(tt.extra)
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.GenMarkdownTree(dir)
+			if !tt.fails {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			_, err = os.Stat(dir)
+			if err == nil {
+				t.Error("pages were written despite the collision")
+			}
+		})
+	}
+}
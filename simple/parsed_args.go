@@ -0,0 +1,193 @@
+package simple
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	gcers "github.com/PlayerR9/errors"
+)
+
+// ParsedArgs is the result of parsing the positional arguments of a command.
+type ParsedArgs struct {
+	// raw is the list of arguments as they were passed on the command line.
+	raw []string
+
+	// values is the map of argument names to their parsed values. Repeating
+	// arguments have more than one value.
+	values map[string][]any
+}
+
+// Raw returns the arguments as they were passed on the command line.
+//
+// Returns:
+//   - []string: A copy of the raw arguments.
+func (pa ParsedArgs) Raw() []string {
+	return slices.Clone(pa.raw)
+}
+
+// Has checks whether the argument with the given name was provided.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - bool: True if the argument was provided, false otherwise.
+func (pa ParsedArgs) Has(name string) bool {
+	return len(pa.values[name]) > 0
+}
+
+// Value returns the parsed value of the argument with the given name. For repeating
+// arguments, the first value is returned.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - any: The parsed value.
+//   - bool: True if the argument was provided, false otherwise.
+func (pa ParsedArgs) Value(name string) (any, bool) {
+	values := pa.values[name]
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	return values[0], true
+}
+
+// Values returns all the parsed values of the argument with the given name.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - []any: A copy of the parsed values. Nil if the argument was not provided.
+func (pa ParsedArgs) Values(name string) []any {
+	return slices.Clone(pa.values[name])
+}
+
+// Get returns the parsed value of the argument with the given name as a value of
+// type T.
+//
+// Parameters:
+//   - pa: The parsed arguments.
+//   - name: The name of the argument.
+//
+// Returns:
+//   - T: The parsed value.
+//   - error: An error if the argument was not provided or is not of type T.
+func Get[T any](pa *ParsedArgs, name string) (T, error) {
+	var zero T
+
+	if pa == nil {
+		return zero, gcers.NewErrNoSuchKey(name)
+	}
+
+	value, ok := pa.Value(name)
+	if !ok {
+		return zero, gcers.NewErrNoSuchKey(name)
+	}
+
+	v, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("argument <%s> is of type %T, not %T", name, value, zero)
+	}
+
+	return v, nil
+}
+
+// GetAll returns all the parsed values of the argument with the given name as
+// values of type T.
+//
+// Parameters:
+//   - pa: The parsed arguments.
+//   - name: The name of the argument.
+//
+// Returns:
+//   - []T: The parsed values. Nil if the argument was not provided.
+//   - error: An error if one of the values is not of type T.
+func GetAll[T any](pa *ParsedArgs, name string) ([]T, error) {
+	if pa == nil {
+		return nil, nil
+	}
+
+	values := pa.values[name]
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	result := make([]T, 0, len(values))
+
+	for _, value := range values {
+		v, ok := value.(T)
+		if !ok {
+			var zero T
+
+			return nil, fmt.Errorf("argument <%s> is of type %T, not %T", name, value, zero)
+		}
+
+		result = append(result, v)
+	}
+
+	return result, nil
+}
+
+// String returns the value of the argument with the given name as a string.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - string: The value of the argument.
+//   - error: An error if the argument was not provided or is not a string.
+func (pa *ParsedArgs) String(name string) (string, error) {
+	return Get[string](pa, name)
+}
+
+// Int returns the value of the argument with the given name as an int.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - int: The value of the argument.
+//   - error: An error if the argument was not provided or is not an int.
+func (pa *ParsedArgs) Int(name string) (int, error) {
+	return Get[int](pa, name)
+}
+
+// Float returns the value of the argument with the given name as a float64.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - float64: The value of the argument.
+//   - error: An error if the argument was not provided or is not a float64.
+func (pa *ParsedArgs) Float(name string) (float64, error) {
+	return Get[float64](pa, name)
+}
+
+// Bool returns the value of the argument with the given name as a bool.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - bool: The value of the argument.
+//   - error: An error if the argument was not provided or is not a bool.
+func (pa *ParsedArgs) Bool(name string) (bool, error) {
+	return Get[bool](pa, name)
+}
+
+// Duration returns the value of the argument with the given name as a time.Duration.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - time.Duration: The value of the argument.
+//   - error: An error if the argument was not provided or is not a time.Duration.
+func (pa *ParsedArgs) Duration(name string) (time.Duration, error) {
+	return Get[time.Duration](pa, name)
+}
@@ -0,0 +1,218 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArgParseFunc is a function that will be executed when a positional argument is
+// parsed.
+//
+// Parameters:
+//   - arg: The argument as it was passed on the command line.
+//
+// Returns:
+//   - any: The parsed value of the argument.
+//   - error: An error if the argument is invalid.
+type ArgParseFunc func(arg string) (any, error)
+
+// Positional is the declaration of a single positional argument.
+type Positional struct {
+	// Name is the name of the argument.
+	Name string
+
+	// ParseFn is the function that parses the argument. If nil, the argument is
+	// kept as a string.
+	ParseFn ArgParseFunc
+
+	// CompleteFn is the function that lists the candidate values of the argument
+	// during shell completion. Leave nil if not needed.
+	CompleteFn CompleteFunc
+
+	// Env is the environment variable the argument falls back to when it is not
+	// given on the command line. If empty, the automatic name is used when
+	// Program.AutoEnv is set; use EnvNone to opt out of it. If the argument repeats,
+	// the variable is split into words as SplitLine does; one word per argument.
+	Env string
+}
+
+// Fix implements the errors.Fixer interface.
+func (pos *Positional) Fix() error {
+	if pos == nil {
+		return nil
+	}
+
+	name := strings.TrimSpace(pos.Name)
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	pos.Name = name
+	pos.Env = strings.TrimSpace(pos.Env)
+
+	if pos.ParseFn == nil {
+		pos.ParseFn = parse_string
+	}
+
+	return nil
+}
+
+// parse_string is the default ArgParseFunc which keeps the argument as is.
+//
+// Parameters:
+//   - arg: The argument.
+//
+// Returns:
+//   - any: The argument as a string.
+//   - error: Always nil.
+func parse_string(arg string) (any, error) {
+	return arg, nil
+}
+
+// StringArg creates a positional argument that is kept as a string.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - *Positional: The positional argument. Never returns nil.
+func StringArg(name string) *Positional {
+	return &Positional{
+		Name:    name,
+		ParseFn: parse_string,
+	}
+}
+
+// IntArg creates a positional argument that is parsed as an int.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - *Positional: The positional argument. Never returns nil.
+func IntArg(name string) *Positional {
+	return &Positional{
+		Name: name,
+		ParseFn: func(arg string) (any, error) {
+			return strconv.Atoi(arg)
+		},
+	}
+}
+
+// FloatArg creates a positional argument that is parsed as a float64.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - *Positional: The positional argument. Never returns nil.
+func FloatArg(name string) *Positional {
+	return &Positional{
+		Name: name,
+		ParseFn: func(arg string) (any, error) {
+			return strconv.ParseFloat(arg, 64)
+		},
+	}
+}
+
+// BoolArg creates a positional argument that is parsed as a bool.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - *Positional: The positional argument. Never returns nil.
+func BoolArg(name string) *Positional {
+	return &Positional{
+		Name: name,
+		ParseFn: func(arg string) (any, error) {
+			return strconv.ParseBool(arg)
+		},
+	}
+}
+
+// DurationArg creates a positional argument that is parsed as a time.Duration.
+// (i.e., "1h30m")
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - *Positional: The positional argument. Never returns nil.
+func DurationArg(name string) *Positional {
+	return &Positional{
+		Name: name,
+		ParseFn: func(arg string) (any, error) {
+			return time.ParseDuration(arg)
+		},
+	}
+}
+
+// PathArg creates a positional argument that is parsed as a cleaned file path.
+// The value is a string.
+//
+// Parameters:
+//   - name: The name of the argument.
+//
+// Returns:
+//   - *Positional: The positional argument. Never returns nil.
+func PathArg(name string) *Positional {
+	return &Positional{
+		Name: name,
+		ParseFn: func(arg string) (any, error) {
+			if arg == "" {
+				return nil, errors.New("path cannot be empty")
+			}
+
+			return filepath.Clean(arg), nil
+		},
+	}
+}
+
+// EnumArg creates a positional argument that must be one of the given choices.
+// The value is a string.
+//
+// Parameters:
+//   - name: The name of the argument.
+//   - choices: The allowed values of the argument.
+//
+// Returns:
+//   - *Positional: The positional argument. Never returns nil.
+func EnumArg(name string, choices ...string) *Positional {
+	choices = slices.Clone(choices)
+
+	return &Positional{
+		Name: name,
+		CompleteFn: func(_ *Program, _ string) []string {
+			return slices.Clone(choices)
+		},
+		ParseFn: func(arg string) (any, error) {
+			ok := slices.Contains(choices, arg)
+			if !ok {
+				return nil, fmt.Errorf("expected one of %s, got %q instead", strings.Join(choices, ", "), arg)
+			}
+
+			return arg, nil
+		},
+	}
+}
+
+// FuncArg creates a positional argument that is parsed with the given function.
+//
+// Parameters:
+//   - name: The name of the argument.
+//   - fn: The function that parses the argument. If nil, the argument is kept as a string.
+//
+// Returns:
+//   - *Positional: The positional argument. Never returns nil.
+func FuncArg(name string, fn ArgParseFunc) *Positional {
+	return &Positional{
+		Name:    name,
+		ParseFn: fn,
+	}
+}
@@ -1,12 +1,14 @@
 package simple
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"iter"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	gcers "github.com/PlayerR9/errors"
 )
@@ -19,13 +21,101 @@ type Program struct {
 	// Version is the version of the program.
 	Version string
 
+	// In is the input stream of the program. If nil, os.Stdin is used.
+	In io.Reader
+
+	// Out is the output stream of the program. If nil, os.Stdout is used.
+	Out io.Writer
+
+	// Err is the error stream of the program. If nil, os.Stderr is used.
+	Err io.Writer
+
+	// LookupEnv is the function used to look up environment variables. If nil,
+	// os.LookupEnv is used.
+	LookupEnv func(key string) (string, bool)
+
+	// ExitCodes is the list of rules that map the errors returned by Run to exit
+	// codes. They are tried before the default mapping. (See ExitCodeOf)
+	ExitCodes []ExitCodeRule
+
+	// Config is the options of the configuration files of the program. If nil, no
+	// configuration file is loaded and --config is not recognized.
+	Config *ConfigOptions
+
+	// Store is the persistent store of settings of the program. If not nil, the
+	// built-in "config" command, with its "get", "set", "unset" and "list"
+	// sub-commands, is added to read and write it. If its path is empty, the store
+	// shares the JSON configuration file of the program; (See ConfigOptions) its
+	// settings are then the fallbacks of the flags and arguments of the same key.
+	Store *Store
+
+	// AutoEnv is true if every argument and flag that does not declare an
+	// environment variable falls back to the one named after the program, the
+	// command and itself. (i.e., TOOL_REMOTE_ADD_URL; see EnvName)
+	//
+	// Precedence is: command line, then environment, then default.
+	AutoEnv bool
+
+	// NoPrompt is true if missing arguments are never prompted for. By default, Run
+	// asks for each required argument that was not given, by name, when both the
+	// input and the output of the program are terminals. (See Command.NoPrompt)
+	NoPrompt bool
+
+	// AllowPrefix is true if any unambiguous prefix of a command's name or alias
+	// resolves to the command. (i.e., "ver" for "version")
+	AllowPrefix bool
+
+	// SuggestionDistance is the maximum edit distance between an unknown command and
+	// the commands suggested in its place. If 0, DefaultSuggestionDistance is used;
+	// if negative, no suggestions are made.
+	SuggestionDistance int
+
+	// ExitPolicy is the policy of the exit sequence. If nil, DefaultExitPolicy is used.
+	ExitPolicy *ExitPolicy
+
+	// Prompt is the prompt of the interactive session. If empty, the name of the
+	// program followed by PromptSuffix is used.
+	Prompt string
+
+	// GracePeriod is the time a command has to return once RunContext cancelled its
+	// context, before the process is forcefully terminated. If 0, DefaultGracePeriod
+	// is used; if negative, only a second signal terminates the process.
+	GracePeriod time.Duration
+
+	// Hooks is the set of hooks that surround the run function of every command.
+	Hooks
+
 	// command_table is the table of commands.
 	command_table map[string]*Command
+
+	// command_order is the names of the commands, in order of registration.
+	command_order []string
+
+	// parsed_args is the parsed arguments of the command being run.
+	parsed_args *ParsedArgs
+
+	// current is the command being run.
+	current *Command
+
+	// ctx is the context of the command being run. Nil if the program was not run
+	// with RunContext.
+	ctx context.Context
+
+	// in_session is true while the program runs an interactive session.
+	in_session bool
+
+	// batch_depth is the number of batches being run, one inside the other. (See
+	// MaxBatchDepth)
+	batch_depth int
+
+	// settings is the merged configuration of the command being run.
+	settings *Settings
 }
 
-// Write implements the io.Writer interface.
+// Write implements the io.Writer interface. It writes to the output stream of the
+// program.
 func (p Program) Write(b []byte) (int, error) {
-	n, err := os.Stdout.Write(b)
+	n, err := p.output().Write(b)
 	if err != nil {
 		return 0, err
 	} else if n != len(b) {
@@ -50,10 +140,52 @@ func (p *Program) Fix() error {
 
 	p.Version = strings.TrimSpace(p.Version)
 
+	if p.In == nil {
+		p.In = os.Stdin
+	}
+
+	if p.Out == nil {
+		p.Out = os.Stdout
+	}
+
+	if p.Err == nil {
+		p.Err = os.Stderr
+	}
+
+	if p.LookupEnv == nil {
+		p.LookupEnv = os.LookupEnv
+	}
+
+	err := gcers.Fix("exit policy", p.ExitPolicy, true)
+	if err != nil {
+		return err
+	}
+
+	err = gcers.Fix("hooks", &p.Hooks, false)
+	if err != nil {
+		return err
+	}
+
+	err = gcers.Fix("config", p.Config, true)
+	if err != nil {
+		return err
+	}
+
+	err = gcers.Fix("store", p.Store, true)
+	if err != nil {
+		return err
+	}
+
+	if p.Store != nil && p.Store.Path == "" {
+		p.Store.Path = p.store_path()
+	}
+
 	if p.command_table == nil {
 		p.command_table = make(map[string]*Command)
 	} else {
-		for k, cmd := range p.command_table {
+		for _, k := range p.command_order {
+			cmd := p.command_table[k]
+
 			err := gcers.Fix("command "+strconv.Quote(k), cmd, false)
 			if err != nil {
 				return err
@@ -63,21 +195,61 @@ func (p *Program) Fix() error {
 
 	// Add version command if needed.
 	if p.Version != "" {
-		ok := p.HasCommand("version")
+		ok := has_name(p.command_table, "version")
 		if !ok {
 			version_cmd := &Command{
-				Name: "version",
+				Name:  "version",
+				Brief: "Prints the version of the program",
 				RunFn: func(p *Program, _ []string) error {
-					_, err := fmt.Println(p.Version)
+					_, err := fmt.Fprintln(p, p.Version)
 					return err
 				},
 				Argument: NoArguments,
+				builtin:  true,
 			}
 
-			p.command_table["version"] = version_cmd
+			p.command_order = add_command(p.command_table, p.command_order, version_cmd)
 		}
 	}
 
+	// Add help command if needed.
+	ok := has_name(p.command_table, "help")
+	if !ok {
+		p.command_order = add_command(p.command_table, p.command_order, new_help_command())
+	}
+
+	// Add completion commands if needed.
+	ok = has_name(p.command_table, "completion")
+	if !ok {
+		p.command_order = add_command(p.command_table, p.command_order, new_completion_command())
+	}
+
+	if p.Store != nil && !has_name(p.command_table, "config") {
+		config_cmd := new_config_command(p.Store)
+
+		err := gcers.Fix("command \"config\"", config_cmd, false)
+		if err != nil {
+			return err
+		}
+
+		p.command_order = add_command(p.command_table, p.command_order, config_cmd)
+	}
+
+	ok = has_name(p.command_table, CompleteCmdName)
+	if !ok {
+		p.command_order = add_command(p.command_table, p.command_order, new_complete_command())
+	}
+
+	err = check_collisions(p.command_table)
+	if err != nil {
+		return err
+	}
+
+	err = check_removals(p.Version, p.top_commands(), p.error_output())
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -101,100 +273,144 @@ func (p *Program) AddCommands(commands ...*Command) {
 			continue
 		}
 
-		p.command_table[command.Name] = command
+		p.command_order = add_command(p.command_table, p.command_order, command)
 	}
 }
 
-// HasCommand checks if the program has a command with the given name.
+// HasCommand checks if the program has a command with the given name or alias.
 //
 // Returns:
 //   - bool: True if the program has a command with the given name, false otherwise.
 func (p Program) HasCommand(name string) bool {
-	if p.command_table == nil {
-		return false
-	}
-
-	_, ok := p.command_table[name]
-	return ok
+	return has_name(p.command_table, name)
 }
 
-// RetrieveCommand retrieves the command with the given name.
+// RetrieveCommand retrieves the command with the given name or alias.
 //
 // Returns:
-//   - *Command: The command with the given name. Never returns nil.
+//   - *Command: The command with the given name. Nil if not found.
 //   - bool: True if the program has a command with the given name, false otherwise.
 func (p Program) RetrieveCommand(name string) (*Command, bool) {
-	if p.command_table == nil {
-		return nil, false
-	}
-
-	cmd, ok := p.command_table[name]
-	if !ok {
+	cmd, _ := lookup_command(p.command_table, name, false)
+	if cmd == nil {
 		return nil, false
 	}
 
 	return cmd, true
 }
 
-// Command is a method that returns an iterator of commands.
+// Command is a method that returns an iterator of commands, in order of
+// registration.
 //
 // Returns:
 //   - iter.Seq2[string, *Command]: The iterator of commands.
 func (p Program) Command() iter.Seq2[string, *Command] {
 	return func(yield func(string, *Command) bool) {
-		for k, cmd := range p.command_table {
-			if !yield(k, cmd) {
+		for _, k := range p.command_order {
+			if !yield(k, p.command_table[k]) {
 				break
 			}
 		}
 	}
 }
 
-// Run is a method that runs the program.
+// Args returns the parsed arguments of the command being run. Only meaningful
+// inside a CmdRunFn.
+//
+// Returns:
+//   - *ParsedArgs: The parsed arguments. Nil if no command is being run.
+func (p Program) Args() *ParsedArgs {
+	return p.parsed_args
+}
+
+// CurrentCommand returns the command being run. Only meaningful inside a CmdRunFn,
+// a hook or a middleware.
+//
+// Returns:
+//   - *Command: The command being run. Nil if no command is being run.
+func (p Program) CurrentCommand() *Command {
+	return p.current
+}
+
+// Run is a method that runs the program. If Config is set, the arguments may start
+// with "--config <file>" to load an explicit configuration file.
 //
 // Parameters:
 //   - args: The arguments to run the program with. This is os.Args.
 //
 // Returns:
-//   - error: The error that occurred. Never returns nil.
+//   - error: The error that occurred. Nil if the command ran successfully.
+//
+// Errors:
+//   - *ErrNoCommand: If no command is provided.
+//   - *ErrUnknownCommand: If the command does not exist.
+//   - *ErrFewArguments, *ErrManyArguments: If the command is given the wrong number
+//     of arguments. Missing arguments are prompted for instead when the program is
+//     interactive, unless NoPrompt is set.
+//   - *ErrInvalidArgument: If an argument cannot be parsed.
+//   - *ErrFlagNotFound, *ErrFlagMissingArg, *ErrInvalidFlag, *ErrFlagConflict: If
+//     the flags are invalid.
+//   - *ErrInvalidEnv, *ErrInvalidSetting: If a fallback holds an invalid value.
+//   - any error that occurred while loading the configuration files. Built-in
+//     commands, such as help, do not load them.
+//   - any error returned by the command.
+//
+// All errors are wrapped with the name of the command; use errors.As to inspect them.
 func (p Program) Run(args []string) error {
+	args, config_path, err := p.split_config_flag(args)
+	if err != nil {
+		return err
+	}
+
 	if len(args) < 2 {
-		_, err := fmt.Println("Usage:", p.Name, "<cmd>")
-		if err != nil {
-			return err
-		}
+		return NewErrNoCommand("")
+	}
 
-		_, err = fmt.Println("Use \"help\" command to see the list of available commands")
-		if err != nil {
-			return err
-		}
+	command := args[1]
 
-		return nil
+	cmd, err := lookup_command(p.command_table, command, p.AllowPrefix)
+	if err != nil {
+		return err
+	} else if cmd == nil {
+		return NewErrUnknownCommand(command, p.suggest(command, p.command_table))
 	}
 
-	command := args[1]
+	cmd, args, err = cmd.resolve(args[2:], p.AllowPrefix)
+	if err != nil {
+		return err
+	}
 
-	cmd, ok := p.command_table[command]
-	if !ok {
-		_, err := fmt.Println("Unknown command: " + command)
-		if err != nil {
-			return err
-		}
+	if len(args) > 0 && len(cmd.sub_commands) > 0 && cmd.Argument.IsEmpty() {
+		return NewErrUnknownCommand(cmd.FullName()+" "+args[0], p.suggest(args[0], cmd.sub_commands))
+	}
+
+	command = cmd.FullName()
 
-		_, err = fmt.Println("Use \"help\" command to see the list of available commands")
+	// Built-in commands do not use the configuration files; they must keep working
+	// when one of them is broken, so that it can be inspected or repaired.
+	if !cmd.is_builtin() {
+		p.settings, err = p.load_settings(config_path)
 		if err != nil {
 			return err
 		}
-
-		return nil
 	}
 
-	args, err := cmd.parse(args[2:])
+	parsed, err := cmd.parse(&p, args)
 	if err != nil {
-		return err
+		return fmt.Errorf("command %q: %w", command, err)
+	}
+
+	p.parsed_args = parsed
+	p.current = cmd
+
+	if cmd.Deprecated != nil {
+		_, err := fmt.Fprintln(p.error_output(), "Warning:", cmd.Deprecated.Warning(command))
+		if err != nil {
+			return err
+		}
 	}
 
-	err = cmd.RunFn(&p, args)
+	err = p.run_command(cmd, parsed.Raw())
 	if err != nil {
 		return fmt.Errorf("command %q failed: %w", command, err)
 	}
@@ -202,6 +418,32 @@ func (p Program) Run(args []string) error {
 	return nil
 }
 
+// ExitCode returns the exit code of the given error according to the program's
+// ExitCodes rules.
+//
+// Parameters:
+//   - err: The error returned by Run.
+//
+// Returns:
+//   - int: The exit code.
+func (p Program) ExitCode(err error) int {
+	return ExitCodeOf(err, p.ExitCodes)
+}
+
+// ExitSequence is like DefaultExitSequence but uses the program's ExitPolicy and
+// ExitCodes rules.
+//
+// Parameters:
+//   - err: The error returned by Run.
+func (p Program) ExitSequence(err error) {
+	policy := p.ExitPolicy
+	if policy == nil {
+		policy = DefaultExitPolicy
+	}
+
+	policy.exit(p.input(), p.output(), p.error_output(), p.lookup_env, err, p.ExitCode(err))
+}
+
 // Print is a method that prints the given arguments. A newline is added at the end.
 //
 // Parameters:
@@ -210,7 +452,7 @@ func (p Program) Run(args []string) error {
 // Returns:
 //   - error: The error that occurred.
 func (p Program) Print(args ...any) error {
-	_, err := fmt.Println(args...)
+	_, err := fmt.Fprintln(p, args...)
 	return err
 }
 
@@ -223,7 +465,7 @@ func (p Program) Print(args ...any) error {
 // Returns:
 //   - error: The error that occurred.
 func (p Program) Printf(format string, args ...any) error {
-	_, err := fmt.Printf(format+"\n", args...)
+	_, err := fmt.Fprintf(p, format+"\n", args...)
 	return err
 }
 
@@ -232,6 +474,98 @@ func (p Program) Printf(format string, args ...any) error {
 // Returns:
 //   - error: The error that occurred.
 func (p Program) PrintNewline() error {
-	_, err := fmt.Println()
+	_, err := fmt.Fprintln(p)
 	return err
 }
+
+// suggest returns the names of the visible commands of the given table that are
+// similar to the given unknown name.
+//
+// Parameters:
+//   - name: The unknown name.
+//   - table: The table of commands the name was looked up in.
+//
+// Returns:
+//   - []string: The suggestions, closest first.
+func (p Program) suggest(name string, table map[string]*Command) []string {
+	distance := p.SuggestionDistance
+	if distance == 0 {
+		distance = DefaultSuggestionDistance
+	}
+
+	candidates := make([]string, 0, len(table))
+
+	for k, cmd := range table {
+		if !cmd.Hidden {
+			candidates = append(candidates, k)
+			candidates = append(candidates, cmd.Aliases...)
+		}
+	}
+
+	return Suggest(name, candidates, distance)
+}
+
+// Getenv returns the value of the environment variable with the given name as seen
+// by the program.
+//
+// Parameters:
+//   - key: The name of the environment variable.
+//
+// Returns:
+//   - string: The value of the variable. Empty if it is not set.
+//   - bool: True if the variable is set, false otherwise.
+func (p Program) Getenv(key string) (string, bool) {
+	return p.lookup_env(key)
+}
+
+// lookup_env looks up the environment variable with the given name.
+//
+// Parameters:
+//   - key: The name of the environment variable.
+//
+// Returns:
+//   - string: The value of the variable.
+//   - bool: True if the variable is set, false otherwise.
+func (p Program) lookup_env(key string) (string, bool) {
+	if p.LookupEnv == nil {
+		return os.LookupEnv(key)
+	}
+
+	return p.LookupEnv(key)
+}
+
+// input returns the input stream of the program.
+//
+// Returns:
+//   - io.Reader: The input stream. Never returns nil.
+func (p Program) input() io.Reader {
+	if p.In == nil {
+		return os.Stdin
+	}
+
+	return p.In
+}
+
+// output returns the output stream of the program.
+//
+// Returns:
+//   - io.Writer: The output stream. Never returns nil.
+func (p Program) output() io.Writer {
+	if p.Out == nil {
+		return os.Stdout
+	}
+
+	return p.Out
+}
+
+// error_output returns the error stream of the program.
+//
+// Returns:
+//   - io.Writer: The error stream. Never returns nil.
+func (p Program) error_output() io.Writer {
+	if p.Err == nil {
+		return os.Stderr
+	}
+
+	return p.Err
+}
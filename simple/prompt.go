@@ -0,0 +1,758 @@
+package simple
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateFn is the function that checks a value entered at a prompt.
+//
+// Parameters:
+//   - value: The value, without surrounding spaces.
+//
+// Returns:
+//   - error: An error if the value is invalid. Its message is shown to the user before
+//     asking again.
+type ValidateFn func(value string) error
+
+// key is a key read from a terminal in raw mode.
+type key int
+
+const (
+	// key_other is any key that has no meaning for the prompts.
+	key_other key = iota
+
+	// key_up is the up arrow or 'k'.
+	key_up
+
+	// key_down is the down arrow or 'j'.
+	key_down
+
+	// key_space is the space bar.
+	key_space
+
+	// key_enter is the enter key.
+	key_enter
+
+	// key_interrupt is Ctrl-C.
+	key_interrupt
+
+	// key_eof is Ctrl-D.
+	key_eof
+
+	// key_escape is ESC, on its own rather than as the start of an escape sequence.
+	key_escape
+)
+
+// IsInteractive checks whether both the input and the output of the program are
+// terminals. The prompts read keys directly from the terminal when they are, and
+// read lines otherwise; which is how they are scripted in tests.
+//
+// Returns:
+//   - bool: True if the program is interactive, false otherwise.
+func (p Program) IsInteractive() bool {
+	return IsTerminal(p.input()) && IsTerminal(p.output())
+}
+
+// Confirm asks a yes/no question. "y", "yes", "n" and "no" are accepted, in any
+// case; an empty answer selects the default.
+//
+// Parameters:
+//   - question: The question. (i.e., "Overwrite the file?")
+//   - def: The default answer.
+//
+// Returns:
+//   - bool: The answer.
+//   - error: An error if the input ended or failed.
+func (p Program) Confirm(question string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+
+	for {
+		answer, err := p.ask(question + " " + hint + ": ")
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+
+		p.reject(fmt.Errorf("please answer yes or no"))
+	}
+}
+
+// Input asks for a line of text. The question is asked again for as long as the
+// validation fails.
+//
+// Parameters:
+//   - label: The label of the value. (i.e., "Name")
+//   - def: The value used when the answer is empty. Empty if there is none.
+//   - validate: The validation function. Nil if every value is valid.
+//
+// Returns:
+//   - string: The value, without surrounding spaces.
+//   - error: An error if the input ended or failed.
+func (p Program) Input(label string, def string, validate ValidateFn) (string, error) {
+	question := label + ": "
+	if def != "" {
+		question = label + " [" + def + "]: "
+	}
+
+	for {
+		answer, err := p.ask(question)
+		if err != nil {
+			return "", err
+		}
+
+		if answer == "" {
+			answer = def
+		}
+
+		if validate != nil {
+			err := validate(answer)
+			if err != nil {
+				p.reject(err)
+				continue
+			}
+		}
+
+		return answer, nil
+	}
+}
+
+// Password asks for a secret. The characters typed are not echoed when the program
+// is interactive. The question is asked again for as long as the validation fails.
+//
+// Parameters:
+//   - label: The label of the secret. (i.e., "Password")
+//   - validate: The validation function. Nil if every value is valid.
+//
+// Returns:
+//   - string: The secret, as typed.
+//   - error: An error if the input ended or failed, or an *ErrInterrupted if Ctrl-C
+//     was pressed.
+func (p Program) Password(label string, validate ValidateFn) (string, error) {
+	for {
+		_, err := fmt.Fprint(p.output(), label+": ")
+		if err != nil {
+			return "", err
+		}
+
+		var secret string
+
+		if p.IsInteractive() {
+			secret, err = p.read_secret()
+		} else {
+			secret, err = read_line(p.input())
+
+			if errors.Is(err, io.EOF) && secret != "" {
+				err = nil
+			}
+		}
+
+		if err != nil {
+			return "", p.end_of_input(err)
+		}
+
+		if validate != nil {
+			err := validate(secret)
+			if err != nil {
+				p.reject(err)
+				continue
+			}
+		}
+
+		return secret, nil
+	}
+}
+
+// Select asks to choose one of the given options. When the program is interactive,
+// the options are chosen with the arrow keys and ENTER; otherwise, they are listed
+// with a number and the answer is either a number or the text of an option.
+//
+// Parameters:
+//   - label: The label of the choice. (i.e., "Pick a color")
+//   - options: The options.
+//   - def: The index of the default option. Negative if there is none.
+//
+// Returns:
+//   - int: The index of the chosen option.
+//   - error: An error if there are no options, if the input ended or failed, or an
+//     *ErrInterrupted if Ctrl-C or ESC was pressed.
+func (p Program) Select(label string, options []string, def int) (int, error) {
+	if len(options) == 0 {
+		return -1, fmt.Errorf("no options to choose from")
+	}
+
+	if def >= len(options) {
+		def = -1
+	}
+
+	if p.IsInteractive() {
+		cursor, _, err := p.menu(label, options, max(def, 0), nil)
+		if !errors.Is(err, errors.ErrUnsupported) {
+			return cursor, err
+		}
+	}
+
+	err := p.list_options(label, options)
+	if err != nil {
+		return -1, err
+	}
+
+	question := fmt.Sprintf("Choose [1-%d]: ", len(options))
+	if def >= 0 {
+		question = fmt.Sprintf("Choose [1-%d] (default %d): ", len(options), def+1)
+	}
+
+	for {
+		answer, err := p.ask(question)
+		if err != nil {
+			return -1, err
+		}
+
+		if answer == "" && def >= 0 {
+			return def, nil
+		}
+
+		idx, err := parse_option(answer, options)
+		if err != nil {
+			p.reject(err)
+			continue
+		}
+
+		return idx, nil
+	}
+}
+
+// MultiSelect asks to choose any number of the given options. When the program is
+// interactive, SPACE toggles the option under the cursor and ENTER confirms;
+// otherwise, the options are listed with a number and the answer is a list of
+// numbers or texts separated by commas.
+//
+// Parameters:
+//   - label: The label of the choice. (i.e., "Pick the features")
+//   - options: The options.
+//   - defaults: The indices of the options selected by default.
+//
+// Returns:
+//   - []int: The indices of the chosen options, in increasing order. Empty if none
+//     was chosen.
+//   - error: An error if there are no options, if the input ended or failed, or an
+//     *ErrInterrupted if Ctrl-C or ESC was pressed.
+func (p Program) MultiSelect(label string, options []string, defaults []int) ([]int, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no options to choose from")
+	}
+
+	selected := make([]bool, len(options))
+
+	for _, idx := range defaults {
+		if idx >= 0 && idx < len(options) {
+			selected[idx] = true
+		}
+	}
+
+	if p.IsInteractive() {
+		_, selected, err := p.menu(label, options, 0, selected)
+		if !errors.Is(err, errors.ErrUnsupported) {
+			if err != nil {
+				return nil, err
+			}
+
+			return selected_indices(selected), nil
+		}
+	}
+
+	err := p.list_options(label, options)
+	if err != nil {
+		return nil, err
+	}
+
+	question := "Choose (comma-separated): "
+
+	if defaults := selected_indices(selected); len(defaults) > 0 {
+		numbers := make([]string, 0, len(defaults))
+
+		for _, idx := range defaults {
+			numbers = append(numbers, strconv.Itoa(idx+1))
+		}
+
+		question = "Choose (comma-separated, default " + strings.Join(numbers, ",") + "): "
+	}
+
+	for {
+		answer, err := p.ask(question)
+		if err != nil {
+			return nil, err
+		}
+
+		if answer == "" {
+			return selected_indices(selected), nil
+		}
+
+		chosen := make([]bool, len(options))
+
+		for _, field := range strings.Split(answer, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+
+			var idx int
+
+			idx, err = parse_option(field, options)
+			if err != nil {
+				break
+			}
+
+			chosen[idx] = true
+		}
+
+		if err != nil {
+			p.reject(err)
+			continue
+		}
+
+		return selected_indices(chosen), nil
+	}
+}
+
+// ask shows the given question and reads a line of the input.
+//
+// Parameters:
+//   - question: The question, with its trailing separator.
+//
+// Returns:
+//   - string: The answer, without surrounding spaces.
+//   - error: An error if the input ended or failed.
+func (p Program) ask(question string) (string, error) {
+	_, err := fmt.Fprint(p.output(), question)
+	if err != nil {
+		return "", err
+	}
+
+	answer, err := read_line(p.input())
+
+	if errors.Is(err, io.EOF) && answer != "" {
+		err = nil
+	}
+
+	if err != nil {
+		return "", p.end_of_input(err)
+	}
+
+	return strings.TrimSpace(answer), nil
+}
+
+// reject reports the reason why an answer was rejected, before the question is
+// asked again.
+//
+// Parameters:
+//   - reason: The reason.
+func (p Program) reject(reason error) {
+	_, _ = fmt.Fprintln(p.error_output(), "Invalid answer:", reason)
+}
+
+// end_of_input prepares the given read error to be returned by a prompt.
+//
+// Parameters:
+//   - err: The read error.
+//
+// Returns:
+//   - error: The error. io.EOF is wrapped so that the prompt that was left unanswered
+//     is reported.
+func (p Program) end_of_input(err error) error {
+	if !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(p.output())
+
+	return fmt.Errorf("no answer was given: %w", err)
+}
+
+// list_options writes the given options, numbered from 1, for the line-based
+// prompts.
+//
+// Parameters:
+//   - label: The label of the choice.
+//   - options: The options.
+//
+// Returns:
+//   - error: An error if the output failed.
+func (p Program) list_options(label string, options []string) error {
+	var builder strings.Builder
+
+	builder.WriteString(label)
+	builder.WriteString(":\n")
+
+	width := len(strconv.Itoa(len(options)))
+
+	for i, option := range options {
+		_, _ = fmt.Fprintf(&builder, "  %*d) %s\n", width, i+1, option)
+	}
+
+	_, err := io.WriteString(p.output(), builder.String())
+	return err
+}
+
+// parse_option parses the answer of a line-based select prompt.
+//
+// Parameters:
+//   - answer: The answer; either the number of an option or its text.
+//   - options: The options.
+//
+// Returns:
+//   - int: The index of the option. -1 if an error occurred.
+//   - error: An error if the answer matches no option.
+func parse_option(answer string, options []string) (int, error) {
+	n, err := strconv.Atoi(answer)
+	if err == nil {
+		if n < 1 || n > len(options) {
+			return -1, fmt.Errorf("%d is not between 1 and %d", n, len(options))
+		}
+
+		return n - 1, nil
+	}
+
+	idx := slices.Index(options, answer)
+	if idx < 0 {
+		return -1, fmt.Errorf("%q is not one of the options", answer)
+	}
+
+	return idx, nil
+}
+
+// selected_indices returns the indices of the selected options.
+//
+// Parameters:
+//   - selected: Whether each option is selected.
+//
+// Returns:
+//   - []int: The indices, in increasing order. Never returns nil.
+func selected_indices(selected []bool) []int {
+	indices := make([]int, 0, len(selected))
+
+	for i, ok := range selected {
+		if ok {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices
+}
+
+// raw_terminal puts the input of the program into raw mode.
+//
+// Returns:
+//   - *os.File: The terminal.
+//   - func() error: The function that restores the previous mode.
+//   - error: An error that wraps errors.ErrUnsupported if raw mode is not available,
+//     in which case the prompts fall back to line-based input.
+func (p Program) raw_terminal() (*os.File, func() error, error) {
+	f, ok := p.input().(*os.File)
+	if !ok {
+		return nil, nil, errors.ErrUnsupported
+	}
+
+	restore, err := make_raw(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errors.ErrUnsupported, err)
+	}
+
+	return f, restore, nil
+}
+
+// read_secret reads a line from the terminal without echoing it. BACKSPACE erases
+// the last character.
+//
+// Returns:
+//   - string: The line.
+//   - error: An error if the input ended or failed, or an *ErrInterrupted if Ctrl-C
+//     was pressed.
+func (p Program) read_secret() (string, error) {
+	f, restore, err := p.raw_terminal()
+	if errors.Is(err, errors.ErrUnsupported) {
+		return read_line(p.input())
+	} else if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_, _ = fmt.Fprintln(p.output())
+	}()
+
+	defer restore()
+
+	var (
+		secret []byte
+		buf    [1]byte
+	)
+
+	for {
+		_, err := f.Read(buf[:])
+		if err != nil {
+			return "", err
+		}
+
+		switch buf[0] {
+		case '\r', '\n':
+			return string(secret), nil
+		case 3:
+			return "", NewErrInterrupted(os.Interrupt)
+		case 4:
+			if len(secret) == 0 {
+				return "", io.EOF
+			}
+		case 8, 127:
+			_, size := utf8.DecodeLastRune(secret)
+			secret = secret[:len(secret)-size]
+		default:
+			secret = append(secret, buf[0])
+		}
+	}
+}
+
+// menu shows the given options and lets the user move between them with the arrow
+// keys until ENTER is pressed. The menu is redrawn in place after every key.
+//
+// Parameters:
+//   - label: The label of the choice.
+//   - options: The options. Must not be empty.
+//   - cursor: The index of the option under the cursor at the start.
+//   - selected: Whether each option is selected, for a multi-select menu; SPACE
+//     toggles the option under the cursor. Nil for a single select menu.
+//
+// Returns:
+//   - int: The index of the option under the cursor when ENTER was pressed.
+//   - []bool: The selected options, as given and updated.
+//   - error: An error if the input ended or failed, an *ErrInterrupted if Ctrl-C or
+//     ESC was pressed, or an error that wraps errors.ErrUnsupported if raw mode is not
+//     available.
+func (p Program) menu(label string, options []string, cursor int, selected []bool) (int, []bool, error) {
+	f, restore, err := p.raw_terminal()
+	if err != nil {
+		return -1, nil, err
+	}
+
+	defer restore()
+
+	out := p.output()
+
+	hint := "(use arrows to move, ENTER to confirm)"
+	if selected != nil {
+		hint = "(use arrows to move, SPACE to toggle, ENTER to confirm)"
+	}
+
+	_, err = fmt.Fprintln(out, label, hint)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	draw := func(redraw bool) error {
+		var builder strings.Builder
+
+		if redraw {
+			_, _ = fmt.Fprintf(&builder, "\x1b[%dA", len(options))
+		}
+
+		for i, option := range options {
+			builder.WriteString("\r\x1b[2K")
+
+			if i == cursor {
+				builder.WriteString("> ")
+			} else {
+				builder.WriteString("  ")
+			}
+
+			if selected != nil {
+				if selected[i] {
+					builder.WriteString("[x] ")
+				} else {
+					builder.WriteString("[ ] ")
+				}
+			}
+
+			builder.WriteString(option)
+			builder.WriteString("\n")
+		}
+
+		_, err := io.WriteString(out, builder.String())
+		return err
+	}
+
+	err = draw(false)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	keys := key_reader{r: f}
+
+	for {
+		k, err := keys.read_key()
+		if err != nil {
+			return -1, nil, err
+		}
+
+		switch k {
+		case key_up:
+			cursor = (cursor - 1 + len(options)) % len(options)
+		case key_down:
+			cursor = (cursor + 1) % len(options)
+		case key_space:
+			if selected != nil {
+				selected[cursor] = !selected[cursor]
+			}
+		case key_enter:
+			return cursor, selected, nil
+		case key_interrupt, key_escape:
+			return -1, nil, NewErrInterrupted(os.Interrupt)
+		case key_eof:
+			return -1, nil, p.end_of_input(io.EOF)
+		default:
+			continue
+		}
+
+		err = draw(true)
+		if err != nil {
+			return -1, nil, err
+		}
+	}
+}
+
+// key_reader reads keys from a terminal in raw mode.
+type key_reader struct {
+	// r is the terminal.
+	r io.Reader
+
+	// buf is the buffer of the reads.
+	buf [16]byte
+
+	// pending is the bytes of buf that were read but not turned into keys yet.
+	pending []byte
+}
+
+// read_key reads the next key.
+//
+// A terminal writes the bytes of a key at once, and they are read together; so an
+// ESC that ends a read is a lone ESC rather than the start of an escape sequence,
+// and it is returned without waiting for more input.
+//
+// Returns:
+//   - key: The key.
+//   - error: An error if the input failed.
+func (kr *key_reader) read_key() (key, error) {
+	for len(kr.pending) == 0 {
+		n, err := kr.r.Read(kr.buf[:])
+		if n == 0 && err != nil {
+			return key_other, err
+		}
+
+		kr.pending = kr.buf[:n]
+	}
+
+	b := kr.pending[0]
+	kr.pending = kr.pending[1:]
+
+	switch b {
+	case '\r', '\n':
+		return key_enter, nil
+	case ' ':
+		return key_space, nil
+	case 'k':
+		return key_up, nil
+	case 'j':
+		return key_down, nil
+	case 3:
+		return key_interrupt, nil
+	case 4:
+		return key_eof, nil
+	case 0x1b:
+		// Arrow keys are sent as "ESC [ A" to "ESC [ D", or "ESC O A" to "ESC O D" in
+		// application mode.
+		if len(kr.pending) < 2 || (kr.pending[0] != '[' && kr.pending[0] != 'O') {
+			return key_escape, nil
+		}
+
+		b = kr.pending[1]
+		kr.pending = kr.pending[2:]
+
+		switch b {
+		case 'A':
+			return key_up, nil
+		case 'B':
+			return key_down, nil
+		}
+	}
+
+	return key_other, nil
+}
+
+// can_prompt checks whether the missing arguments of the given command may be
+// prompted for.
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//
+// Returns:
+//   - bool: True if neither the program nor the command opted out and the program
+//     is interactive, false otherwise.
+func (p Program) can_prompt(cmd *Command) bool {
+	return !p.NoPrompt && !cmd.NoPrompt && p.IsInteractive()
+}
+
+// prompt_args asks for each required argument of the given command that is missing,
+// by the name of its positional argument. Answers are checked with the parse
+// function of the positional argument, and asked again if it fails.
+//
+// Parameters:
+//   - cmd: The command. Assumed to not be nil.
+//   - args: The arguments that were given.
+//
+// Returns:
+//   - []string: The arguments, followed by the answers. Nil if an error occurred.
+//   - error: An error if the input ended or failed.
+func (p Program) prompt_args(cmd *Command, args []string) ([]string, error) {
+	arg := cmd.Argument
+	repeated := arg.min > len(arg.positionals)
+
+	for i := len(args); i < arg.min; i++ {
+		pos := arg.positional_at(i)
+
+		label := pos.Name
+		if repeated {
+			label += " #" + strconv.Itoa(i+1)
+		}
+
+		value, err := p.Input(label, "", func(value string) error {
+			if value == "" {
+				return fmt.Errorf("%s is required", pos.Name)
+			}
+
+			_, err := pos.ParseFn(value)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, value)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,267 @@
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// new_test_line_program creates a program whose prompts read the given input line
+// by line.
+func new_test_line_program(input string, out *bytes.Buffer) Program {
+	return Program{
+		Name: "tool",
+		In:   strings.NewReader(input),
+		Out:  out,
+		Err:  out,
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		def   bool
+		want  bool
+	}{
+		{"y\n", false, true},
+		{"YES\n", false, true},
+		{"no\n", true, false},
+		{"\n", true, true},
+		{"\n", false, false},
+		{"maybe\nn\n", true, false},
+		{"yes", false, true},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+
+		p := new_test_line_program(tt.input, &out)
+
+		got, err := p.Confirm("Continue?", tt.def)
+		if err != nil {
+			t.Errorf("Confirm(%q): unexpected error: %v", tt.input, err)
+		} else if got != tt.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestInput(t *testing.T) {
+	not_empty := func(value string) error {
+		if value == "" {
+			return fmt.Errorf("must not be empty")
+		}
+
+		return nil
+	}
+
+	tests := []struct {
+		input    string
+		def      string
+		validate ValidateFn
+		want     string
+	}{
+		{"  bob  \n", "", nil, "bob"},
+		{"\n", "alice", nil, "alice"},
+		{"\n\ncarol\n", "", not_empty, "carol"},
+		{"dave\r\n", "", nil, "dave"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+
+		p := new_test_line_program(tt.input, &out)
+
+		got, err := p.Input("Name", tt.def, tt.validate)
+		if err != nil {
+			t.Errorf("Input(%q): unexpected error: %v", tt.input, err)
+		} else if got != tt.want {
+			t.Errorf("Input(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPassword(t *testing.T) {
+	var out bytes.Buffer
+
+	p := new_test_line_program("short\n s3cret \n", &out)
+
+	got, err := p.Password("Password", func(value string) error {
+		if len(value) < 6 {
+			return fmt.Errorf("too short")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != " s3cret " {
+		t.Errorf("Password() = %q, want %q", got, " s3cret ")
+	}
+
+	if !strings.Contains(out.String(), "too short") {
+		t.Errorf("output %q does not report the rejected answer", out.String())
+	}
+}
+
+func TestSelect(t *testing.T) {
+	options := []string{"red", "green", "blue"}
+
+	tests := []struct {
+		input string
+		def   int
+		want  int
+	}{
+		{"2\n", -1, 1},
+		{"blue\n", -1, 2},
+		{"\n", 0, 0},
+		{"\n4\nred\n", -1, 0},
+		{"0\n3\n", 5, 2},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+
+		p := new_test_line_program(tt.input, &out)
+
+		got, err := p.Select("Color", options, tt.def)
+		if err != nil {
+			t.Errorf("Select(%q): unexpected error: %v", tt.input, err)
+		} else if got != tt.want {
+			t.Errorf("Select(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	var out bytes.Buffer
+
+	p := new_test_line_program("1\n", &out)
+
+	_, err := p.Select("Color", nil, 0)
+	if err == nil {
+		t.Error("Select() with no options: expected an error")
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	options := []string{"red", "green", "blue"}
+
+	tests := []struct {
+		input    string
+		defaults []int
+		want     []int
+	}{
+		{"3,1\n", nil, []int{0, 2}},
+		{"green, blue\n", nil, []int{1, 2}},
+		{"\n", []int{2, 7}, []int{2}},
+		{"\n", nil, []int{}},
+		{"1,purple\n2\n", nil, []int{1}},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+
+		p := new_test_line_program(tt.input, &out)
+
+		got, err := p.MultiSelect("Colors", options, tt.defaults)
+		if err != nil {
+			t.Errorf("MultiSelect(%q): unexpected error: %v", tt.input, err)
+		} else if !slices.Equal(got, tt.want) {
+			t.Errorf("MultiSelect(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPromptEndOfInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt func(p Program) error
+	}{
+		{"confirm", func(p Program) error {
+			_, err := p.Confirm("Continue?", true)
+			return err
+		}},
+		{"input", func(p Program) error {
+			_, err := p.Input("Name", "bob", nil)
+			return err
+		}},
+		{"password", func(p Program) error {
+			_, err := p.Password("Password", nil)
+			return err
+		}},
+		{"select", func(p Program) error {
+			_, err := p.Select("Color", []string{"red"}, 0)
+			return err
+		}},
+		{"multi-select", func(p Program) error {
+			_, err := p.MultiSelect("Colors", []string{"red"}, nil)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+
+		err := tt.prompt(new_test_line_program("", &out))
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("%s: error = %v, want one that wraps io.EOF", tt.name, err)
+		}
+	}
+}
+
+// chunk_reader is a reader that returns one of its chunks per read, as a terminal
+// returns the bytes of one key per read; and then io.EOF.
+type chunk_reader []string
+
+// Read implements the io.Reader interface.
+func (cr *chunk_reader) Read(b []byte) (int, error) {
+	if len(*cr) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(b, (*cr)[0])
+	*cr = (*cr)[1:]
+
+	return n, nil
+}
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks chunk_reader
+		want   []key
+	}{
+		{"letters", chunk_reader{"j", "k", " ", "x"}, []key{key_down, key_up, key_space, key_other}},
+		{"several keys per read", chunk_reader{"jk\r\x03\x04"}, []key{key_down, key_up, key_enter, key_interrupt, key_eof}},
+		{"arrows", chunk_reader{"\x1b[A", "\x1b[B", "\x1bOA", "\x1bOB", "\x1b[C"}, []key{key_up, key_down, key_up, key_down, key_other}},
+		{"lone escape", chunk_reader{"\x1b", "j"}, []key{key_escape, key_down}},
+		{"escape and a key", chunk_reader{"\x1bj"}, []key{key_escape, key_down}},
+		{"cut sequence", chunk_reader{"\x1b[", "A"}, []key{key_escape, key_other, key_other}},
+	}
+
+	for _, tt := range tests {
+		keys := key_reader{r: &tt.chunks}
+
+		var got []key
+
+		for {
+			k, err := keys.read_key()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			got = append(got, k)
+		}
+
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: keys = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package simple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+const (
+	// PromptSuffix is the suffix of the default prompt of the interactive session;
+	// the default prompt is the name of the program followed by it.
+	PromptSuffix string = "> "
+)
+
+var (
+	// ExitWords is the list of the words that end the interactive session, unless a
+	// command of the program has the same name.
+	ExitWords []string
+)
+
+func init() {
+	ExitWords = []string{"exit", "quit"}
+}
+
+// NewShellCommand creates a command that starts an interactive session. (See
+// Program.RunInteractive) It is not registered by default; add it with
+// Program.AddCommands.
+//
+// Returns:
+//   - *Command: The command. Never returns nil.
+func NewShellCommand() *Command {
+	return &Command{
+		Name:  "shell",
+		Brief: "Starts an interactive session",
+		RunFn: func(p *Program, _ []string) error {
+			return p.RunInteractive()
+		},
+	}
+}
+
+// RunInteractive starts an interactive session: it shows a prompt, reads a line,
+// splits it into words with SplitLine and runs them as the arguments of the program,
+// until one of ExitWords or the end of the input is read. Errors are reported
+// through the exit policy and do not end the session. Each line runs with
+// RunContext, so that a signal cancels the line being run rather than the session;
+// signals received at the prompt are ignored.
+//
+// Returns:
+//   - error: An error if the input or the output failed.
+func (p Program) RunInteractive() error {
+	if p.in_session {
+		return fmt.Errorf("an interactive session is already running")
+	}
+
+	p.in_session = true
+
+	prompt := p.Prompt
+	if prompt == "" {
+		prompt = p.Name + PromptSuffix
+	}
+
+	policy := p.ExitPolicy
+	if policy == nil {
+		policy = DefaultExitPolicy
+	}
+
+	in, out, err_out := p.input(), p.output(), p.error_output()
+
+	signals := make(chan os.Signal, 1)
+
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	level := claim_signals()
+	defer release_signals()
+
+	// The input is read by another goroutine, one line at a time and only when asked
+	// to, so that the session can react to signals while waiting for a line and so
+	// that commands run from the session can still read the input. All writes are
+	// made by this goroutine.
+	requests := make(chan struct{})
+	defer close(requests)
+
+	// Buffered, so that the reader does not block forever on the line it was asked
+	// for when the session ends before taking it.
+	lines := make(chan read_result, 1)
+
+	go func() {
+		for range requests {
+			line, err := read_line(in)
+			lines <- read_result{line: line, err: err}
+		}
+	}()
+
+	for {
+		// Signals received while a line was running were meant for it.
+		for len(signals) > 0 {
+			<-signals
+		}
+
+		_, err := fmt.Fprint(out, prompt)
+		if err != nil {
+			return err
+		}
+
+		requests <- struct{}{}
+
+		var res read_result
+
+		for waiting := true; waiting; {
+			select {
+			case res = <-lines:
+				waiting = false
+			case <-signals:
+				if !owns_signals(level) {
+					continue
+				}
+
+				_, err := fmt.Fprint(out, "\n"+prompt)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		line, read_err := res.line, res.err
+		if read_err != nil && read_err != io.EOF {
+			return read_err
+		}
+
+		words, err := SplitLine(line)
+
+		if err != nil {
+			policy.report(err_out, err)
+		} else if len(words) == 1 && slices.Contains(ExitWords, words[0]) && !has_name(p.command_table, words[0]) {
+			return nil
+		} else if len(words) > 0 {
+			err := p.RunContext(context.Background(), append([]string{p.Name}, words...))
+			if err != nil {
+				policy.report(err_out, err)
+			}
+		}
+
+		if read_err == io.EOF {
+			_, err := fmt.Fprintln(out)
+			return err
+		}
+	}
+}
+
+// read_result is the outcome of read_line.
+type read_result struct {
+	// line is the line that was read.
+	line string
+
+	// err is the error returned by read_line.
+	err error
+}
+
+// read_line reads the given reader up to and excluding the next newline. The input
+// is read one byte at a time so that nothing past the line is consumed; commands
+// run from the session can thus read the same input.
+//
+// Parameters:
+//   - r: The reader.
+//
+// Returns:
+//   - string: The line, without the trailing "\n" or "\r\n".
+//   - error: io.EOF if the input ended before a newline, or the read error.
+func read_line(r io.Reader) (string, error) {
+	var (
+		builder strings.Builder
+		buf     [1]byte
+	)
+
+	for {
+		n, err := r.Read(buf[:])
+
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+
+			builder.WriteByte(buf[0])
+		}
+
+		if errors.Is(err, io.EOF) {
+			return strings.TrimSuffix(builder.String(), "\r"), io.EOF
+		} else if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "\r"), nil
+}
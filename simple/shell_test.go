@@ -0,0 +1,130 @@
+package simple
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// new_test_shell_program creates the program used by the tests of the interactive
+// session. Its "greet" command prints its argument, in upper case if --loud is set.
+func new_test_shell_program(input string, out, err_out *bytes.Buffer) *Program {
+	greet := &Command{
+		Name:     "greet",
+		Argument: NewArgument(StringArg("name")),
+	}
+
+	loud := greet.Flags().Bool("loud", false, "Shout")
+
+	greet.RunFn = func(p *Program, args []string) error {
+		msg := "hello " + args[0]
+		if *loud {
+			msg = strings.ToUpper(msg)
+		}
+
+		_, err := fmt.Fprintln(p, msg)
+		return err
+	}
+
+	p := &Program{
+		Name:       "tool",
+		In:         strings.NewReader(input),
+		Out:        out,
+		Err:        err_out,
+		ExitPolicy: &ExitPolicy{QuietSuccess: true, Pause: PauseNever},
+	}
+
+	p.AddCommands(greet)
+
+	return p
+}
+
+func TestRunInteractive(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		out     string
+		err_out string
+	}{
+		{
+			name:  "exit word",
+			input: "greet bob\nexit\ngreet alice\n",
+			out:   "tool> hello bob\ntool> ",
+		},
+		{
+			name:  "end of input",
+			input: "greet 'bob smith'",
+			out:   "tool> hello bob smith\n\n",
+		},
+		{
+			name:  "flags do not leak between lines",
+			input: "greet --loud bob\ngreet alice\nquit\n",
+			out:   "tool> HELLO BOB\ntool> hello alice\ntool> ",
+		},
+		{
+			name:    "errors do not end the session",
+			input:   "nope\ngreet 'bob\ngreet bob\n",
+			out:     "tool> tool> tool> hello bob\ntool> \n",
+			err_out: "unterminated single quote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, err_out bytes.Buffer
+
+			p := new_test_shell_program(tt.input, &out, &err_out)
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.RunInteractive()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.String() != tt.out {
+				t.Errorf("output = %q, want %q", out.String(), tt.out)
+			}
+
+			if !strings.Contains(err_out.String(), tt.err_out) {
+				t.Errorf("error output = %q, want it to contain %q", err_out.String(), tt.err_out)
+			}
+		})
+	}
+}
+
+func TestSplitLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		words []string
+		fails bool
+	}{
+		{"a b  c", []string{"a", "b", "c"}, false},
+		{`a "b c" 'd e'`, []string{"a", "b c", "d e"}, false},
+		{`a\ b`, []string{"a b"}, false},
+		{`"a\"b"`, []string{`a"b`}, false},
+		{`''`, []string{""}, false},
+		{`"open`, nil, true},
+	}
+
+	for _, tt := range tests {
+		words, err := SplitLine(tt.line)
+		if tt.fails {
+			if err == nil {
+				t.Errorf("SplitLine(%q): expected an error", tt.line)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("SplitLine(%q): unexpected error: %v", tt.line, err)
+		} else if strings.Join(words, "|") != strings.Join(tt.words, "|") || len(words) != len(tt.words) {
+			t.Errorf("SplitLine(%q) = %q, want %q", tt.line, words, tt.words)
+		}
+	}
+}
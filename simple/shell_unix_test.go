@@ -0,0 +1,164 @@
+//go:build unix
+
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// watched_buffer is a buffer that is safe to read while being written to.
+type watched_buffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements the io.Writer interface.
+func (wb *watched_buffer) Write(b []byte) (int, error) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	return wb.buf.Write(b)
+}
+
+// wait_for waits until the buffer contains the given text, or fails the test.
+func (wb *watched_buffer) wait_for(t *testing.T, text string) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		wb.mu.Lock()
+		ok := strings.Contains(wb.buf.String(), text)
+		wb.mu.Unlock()
+
+		if ok {
+			return
+		}
+	}
+
+	t.Fatalf("timed out waiting for %q", text)
+}
+
+func TestRunInteractiveSignalAtPrompt(t *testing.T) {
+	in_reader, in_writer := io.Pipe()
+
+	var out watched_buffer
+
+	p := &Program{
+		Name:       "tool",
+		In:         in_reader,
+		Out:        &out,
+		Err:        io.Discard,
+		ExitPolicy: &ExitPolicy{QuietSuccess: true, Pause: PauseNever},
+	}
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- p.RunInteractive()
+	}()
+
+	out.wait_for(t, "tool> ")
+
+	// The session is waiting for a line once the first byte of the next one is read.
+	_, err = in_writer.Write([]byte("e"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out.wait_for(t, "tool> \ntool> ")
+
+	_, err = in_writer.Write([]byte("xit\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = <-done
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// closing_writer is a writer that fails once it was written to the given number of
+// times, as a closed output does.
+type closing_writer struct {
+	mu     sync.Mutex
+	writes int
+	limit  int
+	ready  chan struct{}
+}
+
+// Write implements the io.Writer interface.
+func (cw *closing_writer) Write(b []byte) (int, error) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	if cw.writes >= cw.limit {
+		return 0, errors.New("output closed")
+	}
+
+	cw.writes++
+
+	if cw.writes == cw.limit {
+		close(cw.ready)
+	}
+
+	return len(b), nil
+}
+
+func TestRunInteractiveOutputClosedAtSignal(t *testing.T) {
+	in_reader, in_writer := io.Pipe()
+	defer in_writer.Close()
+
+	out := &closing_writer{limit: 1, ready: make(chan struct{})}
+
+	p := &Program{
+		Name:       "tool",
+		In:         in_reader,
+		Out:        out,
+		Err:        io.Discard,
+		ExitPolicy: &ExitPolicy{QuietSuccess: true, Pause: PauseNever},
+	}
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- p.RunInteractive()
+	}()
+
+	<-out.ready
+
+	err = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "output closed" {
+			t.Errorf("error = %v, want the error of the output", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the session kept running after its output was closed")
+	}
+}
@@ -0,0 +1,267 @@
+// Package simpletest provides utilities to run a simple.Program in-process and to
+// inspect its outcome from tests.
+package simpletest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	gcers "github.com/PlayerR9/errors"
+
+	"github.com/PlayerR9/LyneCml/simple"
+)
+
+const (
+	// UpdateEnv is the environment variable that, when set to a true value (i.e.,
+	// "1", "true"), makes AssertGolden rewrite the golden files instead of comparing
+	// them. (i.e., SIMPLETEST_UPDATE=1 go test ./...)
+	UpdateEnv string = "SIMPLETEST_UPDATE"
+)
+
+// Options are the inputs of an in-process run.
+type Options struct {
+	// Args are the arguments passed to the program, without the program's name.
+	// (i.e., []string{"remote", "add", "origin"})
+	Args []string
+
+	// Env are the environment variables seen by the program.
+	Env map[string]string
+
+	// InheritEnv is true if the variables of the test process are visible to the
+	// program as well. Variables in Env take precedence.
+	InheritEnv bool
+
+	// Stdin is the content of the input stream of the program.
+	Stdin string
+}
+
+// Result is the outcome of an in-process run.
+type Result struct {
+	// Stdout is everything the program wrote to its output stream.
+	Stdout string
+
+	// Stderr is everything the program wrote to its error stream.
+	Stderr string
+
+	// Err is the error returned by Program.Run.
+	Err error
+
+	// ExitCode is the exit code the program would have exited with.
+	ExitCode int
+}
+
+// Run runs the given program in-process with the given options. The program is
+// fixed in place, as Program.Run requires, but its streams and environment are left
+// untouched: the run uses a copy of the program with the ones of the options. The
+// copy shares the commands of the program; runs of the same program must thus not
+// be concurrent.
+//
+// Parameters:
+//   - p: The program to run.
+//   - opts: The inputs of the run.
+//
+// Returns:
+//   - *Result: The outcome of the run. Nil if the program could not be fixed.
+//   - error: An error if the program is nil or could not be fixed.
+func Run(p *simple.Program, opts Options) (*Result, error) {
+	if p == nil {
+		return nil, gcers.NewErrNilParameter("p")
+	}
+
+	err := gcers.Fix("program", p, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return run(p, opts), nil
+}
+
+// run runs the given fixed program in-process with the given options.
+//
+// Parameters:
+//   - p: The program to run. Assumed to be fixed.
+//   - opts: The inputs of the run.
+//
+// Returns:
+//   - *Result: The outcome of the run. Never returns nil.
+func run(p *simple.Program, opts Options) *Result {
+	var stdout, stderr bytes.Buffer
+
+	cp := *p
+
+	cp.In = strings.NewReader(opts.Stdin)
+	cp.Out = &stdout
+	cp.Err = &stderr
+	cp.LookupEnv = lookup_env(opts.Env, opts.InheritEnv)
+
+	args := make([]string, 0, len(opts.Args)+1)
+	args = append(args, cp.Name)
+	args = append(args, opts.Args...)
+
+	err := cp.Run(args)
+
+	res := &Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Err:      err,
+		ExitCode: cp.ExitCode(err),
+	}
+
+	return res
+}
+
+// lookup_env creates the function used by the program to look up environment
+// variables.
+//
+// Parameters:
+//   - env: The variables of the run.
+//   - inherit: Whether the variables of the test process are visible as well.
+//
+// Returns:
+//   - func(string) (string, bool): The lookup function. Never returns nil.
+func lookup_env(env map[string]string, inherit bool) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		if ok {
+			return value, true
+		}
+
+		if !inherit {
+			return "", false
+		}
+
+		return os.LookupEnv(key)
+	}
+}
+
+// String returns the golden representation of the result; that is, its output
+// streams and its exit code in separate sections.
+//
+// Returns:
+//   - string: The golden representation of the result.
+func (r Result) String() string {
+	var builder strings.Builder
+
+	builder.WriteString("-- stdout --\n")
+	builder.WriteString(r.Stdout)
+	builder.WriteString("-- stderr --\n")
+	builder.WriteString(r.Stderr)
+	builder.WriteString("-- error --\n")
+
+	if r.Err != nil {
+		builder.WriteString(r.Err.Error())
+		builder.WriteRune('\n')
+	}
+
+	builder.WriteString("-- exit code --\n")
+	builder.WriteString(strconv.Itoa(r.ExitCode))
+	builder.WriteRune('\n')
+
+	return builder.String()
+}
+
+// AssertGolden compares the golden representation of the result with the content of
+// the given file and fails the test if they differ. When the UpdateEnv environment
+// variable is set to a true value, the file is rewritten instead.
+//
+// Parameters:
+//   - t: The test.
+//   - path: The path of the golden file. (i.e., "testdata/help.golden")
+func (r Result) AssertGolden(t testing.TB, path string) {
+	t.Helper()
+
+	got := r.String()
+
+	if should_update() {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("could not create the directory of %q: %s", path, err.Error())
+		}
+
+		err = os.WriteFile(path, []byte(got), 0644)
+		if err != nil {
+			t.Fatalf("could not update %q: %s", path, err.Error())
+		}
+
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("golden file %q does not exist; run the tests with %s=1 to create it", path, UpdateEnv)
+	} else if err != nil {
+		t.Fatalf("could not read %q: %s", path, err.Error())
+	}
+
+	want := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	if got != want {
+		t.Errorf("result does not match %q:\n--- got ---\n%s--- want ---\n%s", path, got, want)
+	}
+}
+
+// should_update checks whether the golden files must be rewritten.
+//
+// Returns:
+//   - bool: True if UpdateEnv is set to a true value, false otherwise.
+func should_update() bool {
+	update, err := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return err == nil && update
+}
+
+// Case is a single case of a table-driven test.
+type Case struct {
+	// Name is the name of the sub-test.
+	Name string
+
+	// Options are the inputs of the run.
+	Options
+
+	// ExitCode is the expected exit code.
+	ExitCode int
+
+	// Stdout is the expected content of the output stream. Ignored if empty and
+	// Golden is set.
+	Stdout string
+
+	// Golden is the path of the golden file to compare the result with. Leave
+	// empty to compare Stdout instead.
+	Golden string
+}
+
+// RunCases runs every case as a sub-test of t against the given program, one after
+// the other. (See Run)
+//
+// Parameters:
+//   - t: The test.
+//   - p: The program to run.
+//   - cases: The cases to run.
+func RunCases(t *testing.T, p *simple.Program, cases []Case) {
+	t.Helper()
+
+	err := gcers.Fix("program", p, false)
+	if err != nil {
+		t.Fatalf("could not fix the program: %s", err.Error())
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			res := run(p, c.Options)
+
+			if res.ExitCode != c.ExitCode {
+				t.Errorf("want exit code %d, got %d (error: %v)", c.ExitCode, res.ExitCode, res.Err)
+			}
+
+			if c.Golden != "" {
+				res.AssertGolden(t, c.Golden)
+			} else if res.Stdout != c.Stdout {
+				t.Errorf("want stdout %q, got %q", c.Stdout, res.Stdout)
+			}
+		})
+	}
+}
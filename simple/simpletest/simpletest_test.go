@@ -0,0 +1,122 @@
+package simpletest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PlayerR9/LyneCml/simple"
+)
+
+// new_test_program creates the program used by the tests of this package. Its
+// "greet" command greets its --name flag, louder with --verbose.
+func new_test_program() *simple.Program {
+	greet := &simple.Command{Name: "greet"}
+
+	verbose := greet.Flags().Bool("verbose", false, "Greet louder", simple.WithShortName('v'))
+	name := greet.Flags().String("name", "world", "Name to greet", simple.WithEnv("GREET_NAME"))
+
+	greet.RunFn = func(p *simple.Program, _ []string) error {
+		msg := "hello " + *name
+
+		if *verbose {
+			msg = strings.ToUpper(msg)
+		}
+
+		_, err := fmt.Fprintln(p, msg)
+		return err
+	}
+
+	p := &simple.Program{
+		Name:    "tool",
+		Version: "1.0.0",
+	}
+
+	p.AddCommands(greet)
+
+	return p
+}
+
+func TestRunCasesInSequence(t *testing.T) {
+	p := new_test_program()
+
+	RunCases(t, p, []Case{
+		{Name: "verbose", Options: Options{Args: []string{"greet", "-v"}}, Stdout: "HELLO WORLD\n"},
+		{Name: "flags do not leak", Options: Options{Args: []string{"greet"}}, Stdout: "hello world\n"},
+		{Name: "environment", Options: Options{Args: []string{"greet"}, Env: map[string]string{"GREET_NAME": "bob"}}, Stdout: "hello bob\n"},
+		{Name: "environment does not leak", Options: Options{Args: []string{"greet"}}, Stdout: "hello world\n"},
+		{Name: "unknown flag", Options: Options{Args: []string{"greet", "--nope"}}, ExitCode: simple.ExitUsage},
+	})
+}
+
+func TestRunKeepsBuiltins(t *testing.T) {
+	p := new_test_program()
+
+	for i := 0; i < 2; i++ {
+		res, err := Run(p, Options{Args: []string{"help"}})
+		if err != nil {
+			t.Fatalf("run %d: unexpected error: %v", i+1, err)
+		}
+
+		for _, name := range []string{"greet", "help", "version", "completion"} {
+			if !strings.Contains(res.Stdout, name) {
+				t.Errorf("run %d: help output does not list %q:\n%s", i+1, name, res.Stdout)
+			}
+		}
+	}
+}
+
+func TestRunLeavesStreams(t *testing.T) {
+	var out bytes.Buffer
+
+	p := new_test_program()
+	p.Out = &out
+	p.Err = &out
+
+	res, err := Run(p, Options{Args: []string{"greet"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Stdout != "hello world\n" {
+		t.Errorf("stdout = %q, want %q", res.Stdout, "hello world\n")
+	}
+
+	if p.Out != &out || p.Err != &out || out.Len() != 0 {
+		t.Errorf("the streams of the program were used or replaced: %q", out.String())
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	t.Setenv(UpdateEnv, "")
+
+	res, err := Run(new_test_program(), Options{Args: []string{"greet", "--name", "golden"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	res.AssertGolden(t, "testdata/greet.golden")
+}
+
+func TestShouldUpdate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"0", false},
+		{"nope", false},
+		{"1", true},
+		{"true", true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(UpdateEnv, tt.value)
+
+		got := should_update()
+		if got != tt.want {
+			t.Errorf("should_update() with %s=%q = %v, want %v", UpdateEnv, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package simple
+
+// ValueSource is the source of the value of a flag or argument.
+type ValueSource int
+
+const (
+	// SourceDefault is the source of a value that was not set; it is the default.
+	SourceDefault ValueSource = iota
+
+	// SourceConfig is the source of a value that was set from a configuration file.
+	SourceConfig
+
+	// SourceEnv is the source of a value that was set from an environment variable.
+	SourceEnv
+
+	// SourceCommandLine is the source of a value that was set on the command line.
+	SourceCommandLine
+)
+
+// String implements the fmt.Stringer interface.
+func (vs ValueSource) String() string {
+	switch vs {
+	case SourceDefault:
+		return "default"
+	case SourceConfig:
+		return "config"
+	case SourceEnv:
+		return "env"
+	case SourceCommandLine:
+		return "command line"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,201 @@
+package simple
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExpandFunc returns the value of the variable with the given name.
+//
+// Parameters:
+//   - name: The name of the variable.
+//
+// Returns:
+//   - string: The value of the variable.
+//   - error: An error if the variable cannot be expanded.
+type ExpandFunc func(name string) (string, error)
+
+// SplitLine splits the given line into words the way a POSIX shell does, without
+// any expansion:
+//   - words are separated by unquoted blanks (spaces and tabs);
+//   - single quotes preserve every character up to the closing quote;
+//   - double quotes preserve every character up to the closing quote, except for
+//     a backslash followed by '"', '\\', '$' or '`', which is replaced by that
+//     character;
+//   - outside of quotes, a backslash preserves the character that follows it;
+//   - an unquoted '#' at the start of a word starts a comment that runs to the end
+//     of the line.
+//
+// Parameters:
+//   - line: The line to split.
+//
+// Returns:
+//   - []string: The words of the line. Nil if the line is blank.
+//   - error: An error if a quote is not closed or the line ends with a backslash.
+func SplitLine(line string) ([]string, error) {
+	return split_line(line, nil)
+}
+
+// split_line is like SplitLine, but also replaces every "${NAME}" that is not in
+// single quotes nor escaped by the value of the variable NAME, as returned by the
+// given function. A "${NAME:-default}" is replaced by the default when the function
+// fails. Expanded values are taken literally: they are neither split nor unquoted.
+//
+// Parameters:
+//   - line: The line to split.
+//   - expand: The function that expands variables. If nil, nothing is expanded.
+//
+// Returns:
+//   - []string: The words of the line. Nil if the line is blank.
+//   - error: An error if the line is malformed or a variable cannot be expanded.
+func split_line(line string, expand ExpandFunc) ([]string, error) {
+	var (
+		words   []string
+		builder strings.Builder
+		in_word bool
+	)
+
+	chars := []rune(line)
+
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			if in_word {
+				words = append(words, builder.String())
+				builder.Reset()
+				in_word = false
+			}
+		case '#':
+			if !in_word {
+				i = len(chars)
+				continue
+			}
+
+			builder.WriteRune(c)
+		case '\\':
+			if i+1 == len(chars) {
+				return nil, fmt.Errorf("line ends with an unescaped backslash")
+			}
+
+			i++
+			builder.WriteRune(chars[i])
+			in_word = true
+		case '\'':
+			end := index_rune(chars, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote at column %d", i+1)
+			}
+
+			builder.WriteString(string(chars[i+1 : end]))
+			i = end
+			in_word = true
+		case '"':
+			j := i + 1
+
+			for ; j < len(chars) && chars[j] != '"'; j++ {
+				if chars[j] == '\\' && j+1 < len(chars) && strings.ContainsRune("\"\\$`", chars[j+1]) {
+					j++
+					builder.WriteRune(chars[j])
+
+					continue
+				}
+
+				n, err := expand_var(&builder, chars, j, expand)
+				if err != nil {
+					return nil, err
+				} else if n > 0 {
+					j += n - 1
+				} else {
+					builder.WriteRune(chars[j])
+				}
+			}
+
+			if j == len(chars) {
+				return nil, fmt.Errorf("unterminated double quote at column %d", i+1)
+			}
+
+			i = j
+			in_word = true
+		default:
+			n, err := expand_var(&builder, chars, i, expand)
+			if err != nil {
+				return nil, err
+			} else if n > 0 {
+				i += n - 1
+			} else {
+				builder.WriteRune(c)
+			}
+
+			in_word = true
+		}
+	}
+
+	if in_word {
+		words = append(words, builder.String())
+	}
+
+	return words, nil
+}
+
+// expand_var writes the value of the "${NAME}" or "${NAME:-default}" that starts at
+// the given index, if any.
+//
+// Parameters:
+//   - builder: The builder to write the value to.
+//   - chars: The runes of the line.
+//   - idx: The index of the '$'.
+//   - expand: The function that expands variables. If nil, nothing is expanded.
+//
+// Returns:
+//   - int: The number of runes of the expanded reference. 0 if there is none.
+//   - error: An error if the reference is malformed or cannot be expanded.
+func expand_var(builder *strings.Builder, chars []rune, idx int, expand ExpandFunc) (int, error) {
+	if expand == nil || chars[idx] != '$' || idx+1 == len(chars) || chars[idx+1] != '{' {
+		return 0, nil
+	}
+
+	end := index_rune(chars, idx+2, '}')
+	if end < 0 {
+		return 0, fmt.Errorf("unterminated variable reference at column %d", idx+1)
+	}
+
+	name, def_value, has_default := strings.Cut(string(chars[idx+2:end]), ":-")
+	if name == "" {
+		return 0, fmt.Errorf("empty variable reference at column %d", idx+1)
+	}
+
+	value, err := expand(name)
+	if err != nil {
+		if !has_default {
+			return 0, err
+		}
+
+		value = def_value
+	}
+
+	builder.WriteString(value)
+
+	return end - idx + 1, nil
+}
+
+// index_rune returns the index of the first occurrence of the given rune at or
+// after the given index.
+//
+// Parameters:
+//   - chars: The runes to search.
+//   - from: The index to start from.
+//   - target: The rune to find.
+//
+// Returns:
+//   - int: The index of the rune. -1 if not found.
+func index_rune(chars []rune, from int, target rune) int {
+	for i := from; i < len(chars); i++ {
+		if chars[i] == target {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,782 @@
+package simple
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SettingType is the type of the value of a setting.
+type SettingType int
+
+const (
+	// SettingString is the type of string settings.
+	SettingString SettingType = iota
+
+	// SettingInt is the type of int settings.
+	SettingInt
+
+	// SettingFloat is the type of float64 settings.
+	SettingFloat
+
+	// SettingBool is the type of bool settings.
+	SettingBool
+
+	// SettingDuration is the type of time.Duration settings. They are stored as
+	// strings. (i.e., "1m30s")
+	SettingDuration
+)
+
+// String implements the fmt.Stringer interface.
+func (st SettingType) String() string {
+	switch st {
+	case SettingString:
+		return "string"
+	case SettingInt:
+		return "int"
+	case SettingFloat:
+		return "float"
+	case SettingBool:
+		return "bool"
+	case SettingDuration:
+		return "duration"
+	default:
+		return "unknown"
+	}
+}
+
+// parse parses the given string as a value of the type.
+//
+// Parameters:
+//   - str: The string to parse.
+//
+// Returns:
+//   - any: The parsed value.
+//   - error: An error if the string is not a valid value of the type.
+func (st SettingType) parse(str string) (any, error) {
+	switch st {
+	case SettingString:
+		return str, nil
+	case SettingInt:
+		return strconv.Atoi(str)
+	case SettingFloat:
+		return strconv.ParseFloat(str, 64)
+	case SettingBool:
+		return strconv.ParseBool(str)
+	case SettingDuration:
+		return time.ParseDuration(str)
+	default:
+		return nil, fmt.Errorf("unknown setting type %d", st)
+	}
+}
+
+// convert converts the given value, as stored in the file or given as a default,
+// to a value of the type.
+//
+// Parameters:
+//   - value: The value to convert.
+//
+// Returns:
+//   - any: The converted value.
+//   - error: An error if the value is not of the type.
+func (st SettingType) convert(value any) (any, error) {
+	switch st {
+	case SettingString:
+		str, ok := value.(string)
+		if ok {
+			return str, nil
+		}
+	case SettingInt:
+		switch n := value.(type) {
+		case int:
+			return n, nil
+		case float64:
+			if n == math.Trunc(n) && n >= math.MinInt && n <= math.MaxInt {
+				return int(n), nil
+			}
+		}
+	case SettingFloat:
+		switch n := value.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		}
+	case SettingBool:
+		b, ok := value.(bool)
+		if ok {
+			return b, nil
+		}
+	case SettingDuration:
+		switch d := value.(type) {
+		case time.Duration:
+			return d, nil
+		case string:
+			return time.ParseDuration(d)
+		}
+	}
+
+	return nil, fmt.Errorf("value %v is not of type %s", value, st)
+}
+
+// SettingSpec is the declaration of a setting of a Store.
+type SettingSpec struct {
+	// Key is the dotted key of the setting. (i.e., "greet.count")
+	Key string
+
+	// Type is the type of the value of the setting.
+	Type SettingType
+
+	// Default is the value of the setting when it is not set. If nil, the zero
+	// value of the type is used.
+	Default any
+
+	// Description is the description of the setting. Leave empty if not needed.
+	Description string
+
+	// Validate checks a value of the setting before it is set. Leave nil if not
+	// needed.
+	Validate func(value any) error
+}
+
+// Fix implements the errors.Fixer interface.
+func (ss *SettingSpec) Fix() error {
+	if ss == nil {
+		return nil
+	}
+
+	ss.Key = strings.TrimSpace(ss.Key)
+	if ss.Key == "" {
+		return errors.New("key cannot be empty")
+	}
+
+	ss.Description = strings.TrimSpace(ss.Description)
+
+	if ss.Default == nil {
+		zero, err := ss.Type.parse(zero_strings[ss.Type])
+		if err != nil {
+			return err
+		}
+
+		ss.Default = zero
+	} else {
+		def_value, err := ss.Type.convert(ss.Default)
+		if err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+
+		ss.Default = def_value
+	}
+
+	return nil
+}
+
+// zero_strings is the string of the zero value of each type.
+var zero_strings = map[SettingType]string{
+	SettingString:   "",
+	SettingInt:      "0",
+	SettingFloat:    "0",
+	SettingBool:     "false",
+	SettingDuration: "0s",
+}
+
+// Store is a persistent store of typed settings, backed by a JSON object in a
+// file. Entries of the file that are not registered are preserved.
+//
+// Keys are read as the configuration files are: nested objects give dotted keys.
+// (i.e., {"greet": {"count": 3}} sets "greet.count") Settings are written as nested
+// objects.
+//
+// When the store shares the file of the configuration files of the program (see
+// Program.Store), its settings are also the fallbacks of the flags and arguments
+// of the same key.
+type Store struct {
+	// Path is the path of the JSON file. If empty, Program.Fix sets it to
+	// "<dir>/<name>.json", where dir and name are those of Program.Config or their
+	// defaults.
+	Path string
+
+	// specs is the map of keys to their declaration.
+	specs map[string]*SettingSpec
+
+	// order is the keys of the settings, in order of registration.
+	order []string
+
+	// raw is the content of the file.
+	raw map[string]any
+
+	// mu protects raw.
+	mu sync.Mutex
+}
+
+// NewStore creates a new store with the given settings.
+//
+// Parameters:
+//   - specs: The declarations of the settings. Nil declarations are ignored.
+//
+// Returns:
+//   - *Store: The new store. Never returns nil.
+func NewStore(specs ...*SettingSpec) *Store {
+	s := &Store{}
+
+	s.Register(specs...)
+
+	return s
+}
+
+// Register registers settings. Registering a key again replaces its declaration.
+//
+// Parameters:
+//   - specs: The declarations of the settings. Nil declarations are ignored.
+func (s *Store) Register(specs ...*SettingSpec) {
+	if s == nil {
+		return
+	}
+
+	if s.specs == nil {
+		s.specs = make(map[string]*SettingSpec)
+	}
+
+	for _, spec := range specs {
+		if spec == nil {
+			continue
+		}
+
+		spec.Key = strings.TrimSpace(spec.Key)
+
+		_, ok := s.specs[spec.Key]
+		if !ok {
+			s.order = append(s.order, spec.Key)
+		}
+
+		s.specs[spec.Key] = spec
+	}
+}
+
+// Fix implements the errors.Fixer interface.
+func (s *Store) Fix() error {
+	if s == nil {
+		return nil
+	}
+
+	s.Path = strings.TrimSpace(s.Path)
+
+	for _, key := range s.order {
+		err := s.specs[key].Fix()
+		if err != nil {
+			return fmt.Errorf("setting %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// Specs returns the declarations of the settings.
+//
+// Returns:
+//   - []*SettingSpec: The declarations, in order of registration.
+func (s *Store) Specs() []*SettingSpec {
+	specs := make([]*SettingSpec, 0, len(s.order))
+
+	for _, key := range s.order {
+		specs = append(specs, s.specs[key])
+	}
+
+	return specs
+}
+
+// Load reads the file of the store. A missing file counts as an empty one.
+//
+// Returns:
+//   - error: An error if the file could not be read or is not a JSON object.
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+// load is Load without locking.
+func (s *Store) load() error {
+	raw := make(map[string]any)
+
+	data, err := os.ReadFile(s.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	} else if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		err := json.Unmarshal(data, &raw)
+		if err != nil {
+			return fmt.Errorf("settings file %q: %w", s.Path, err)
+		}
+	}
+
+	s.raw = raw
+
+	return nil
+}
+
+// save writes the file of the store atomically; that is, through a temporary file
+// that replaces the previous one. Must be called with the lock held.
+//
+// Returns:
+//   - error: An error if the file could not be written.
+func (s *Store) save() error {
+	if s.Path == "" {
+		return errors.New("the store has no path")
+	}
+
+	data, err := json.MarshalIndent(s.raw, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.Path)
+
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(append(data, '\n'))
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	close_err := tmp.Close()
+	if err == nil {
+		err = close_err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// spec returns the declaration of the given key.
+//
+// Parameters:
+//   - key: The key.
+//
+// Returns:
+//   - *SettingSpec: The declaration.
+//   - error: An *ErrUnknownSetting if the key is not registered.
+func (s *Store) spec(key string) (*SettingSpec, error) {
+	spec, ok := s.specs[key]
+	if !ok {
+		return nil, NewErrUnknownSetting(key, Suggest(key, s.order, DefaultSuggestionDistance))
+	}
+
+	return spec, nil
+}
+
+// Get returns the value of the given setting, or its default if it is not set. The
+// file is read on the first access.
+//
+// Parameters:
+//   - key: The key of the setting.
+//
+// Returns:
+//   - any: The value, of the Go type of the setting's type.
+//   - error: An *ErrUnknownSetting if the key is not registered, or an error if the
+//     file could not be read or holds an invalid value.
+func (s *Store) Get(key string) (any, error) {
+	spec, err := s.spec(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.raw == nil {
+		err := s.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	raw, ok := lookup_setting(s.raw, key)
+	if !ok {
+		return spec.Default, nil
+	}
+
+	value, err := spec.Type.convert(raw)
+	if err != nil {
+		return nil, NewErrInvalidSetting(key, s.Path, err)
+	}
+
+	return value, nil
+}
+
+// IsSet checks whether the given setting is set in the file. The file is read on
+// the first access.
+//
+// Parameters:
+//   - key: The key of the setting.
+//
+// Returns:
+//   - bool: True if the setting is set, false otherwise.
+//   - error: An error if the file could not be read.
+func (s *Store) IsSet(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.raw == nil {
+		err := s.load()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	_, ok := lookup_setting(s.raw, key)
+	return ok, nil
+}
+
+// Set parses, validates and sets the value of the given setting, and then writes
+// the file.
+//
+// Parameters:
+//   - key: The key of the setting.
+//   - str: The value, as written on the command line.
+//
+// Returns:
+//   - error: An *ErrUnknownSetting if the key is not registered, an
+//     *ErrInvalidSetting if the value is invalid or if the file holds a value that
+//     is not an object where the key needs one, or an error if the file could not
+//     be read or written.
+func (s *Store) Set(key, str string) error {
+	spec, err := s.spec(key)
+	if err != nil {
+		return err
+	}
+
+	value, err := spec.Type.parse(str)
+	if err != nil {
+		return NewErrInvalidSetting(key, "", err)
+	}
+
+	if spec.Validate != nil {
+		err := spec.Validate(value)
+		if err != nil {
+			return NewErrInvalidSetting(key, "", err)
+		}
+	}
+
+	d, ok := value.(time.Duration)
+	if ok {
+		value = d.String()
+	}
+
+	return s.update(func(raw map[string]any) (bool, error) {
+		delete_setting(raw, key)
+
+		err := insert_setting(raw, key, value)
+		if err != nil {
+			return false, NewErrInvalidSetting(key, s.Path, err)
+		}
+
+		return true, nil
+	})
+}
+
+// Unset removes the given setting from the file, which restores its default.
+//
+// Parameters:
+//   - key: The key of the setting.
+//
+// Returns:
+//   - error: An *ErrUnknownSetting if the key is not registered, or an error if the
+//     file could not be read or written.
+func (s *Store) Unset(key string) error {
+	_, err := s.spec(key)
+	if err != nil {
+		return err
+	}
+
+	return s.update(func(raw map[string]any) (bool, error) {
+		return delete_setting(raw, key), nil
+	})
+}
+
+// update reads the file, changes its content with fn and then writes it if fn
+// changed it. If any step fails, the file is read again on the next access.
+//
+// Parameters:
+//   - fn: The function that changes the content of the file. It returns whether it
+//     changed it.
+//
+// Returns:
+//   - error: An error if the file could not be read or written, or the error of fn.
+func (s *Store) update(fn func(raw map[string]any) (bool, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return err
+	}
+
+	changed, err := fn(s.raw)
+	if err == nil && changed {
+		err = s.save()
+	}
+
+	if err != nil {
+		s.raw = nil
+	}
+
+	return err
+}
+
+// String returns the value of the given string setting.
+//
+// Parameters:
+//   - key: The key of the setting.
+//
+// Returns:
+//   - string: The value.
+//   - error: An error if the value could not be read or is not a string.
+func (s *Store) String(key string) (string, error) {
+	return get_setting[string](s, key)
+}
+
+// Int returns the value of the given int setting.
+//
+// Parameters:
+//   - key: The key of the setting.
+//
+// Returns:
+//   - int: The value.
+//   - error: An error if the value could not be read or is not an int.
+func (s *Store) Int(key string) (int, error) {
+	return get_setting[int](s, key)
+}
+
+// Float returns the value of the given float setting.
+//
+// Parameters:
+//   - key: The key of the setting.
+//
+// Returns:
+//   - float64: The value.
+//   - error: An error if the value could not be read or is not a float.
+func (s *Store) Float(key string) (float64, error) {
+	return get_setting[float64](s, key)
+}
+
+// Bool returns the value of the given bool setting.
+//
+// Parameters:
+//   - key: The key of the setting.
+//
+// Returns:
+//   - bool: The value.
+//   - error: An error if the value could not be read or is not a bool.
+func (s *Store) Bool(key string) (bool, error) {
+	return get_setting[bool](s, key)
+}
+
+// Duration returns the value of the given duration setting.
+//
+// Parameters:
+//   - key: The key of the setting.
+//
+// Returns:
+//   - time.Duration: The value.
+//   - error: An error if the value could not be read or is not a duration.
+func (s *Store) Duration(key string) (time.Duration, error) {
+	return get_setting[time.Duration](s, key)
+}
+
+// get_setting returns the value of the given setting as a value of type T.
+//
+// Parameters:
+//   - s: The store.
+//   - key: The key of the setting.
+//
+// Returns:
+//   - T: The value.
+//   - error: An error if the value could not be read or is not of type T.
+func get_setting[T any](s *Store, key string) (T, error) {
+	var zero T
+
+	value, err := s.Get(key)
+	if err != nil {
+		return zero, err
+	}
+
+	res, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("setting %q is of type %s, not %T", key, s.specs[key].Type, zero)
+	}
+
+	return res, nil
+}
+
+// store_path returns the default path of the store of the program.
+//
+// Returns:
+//   - string: The path. Empty if the user's configuration directory is unknown.
+func (p Program) store_path() string {
+	name := DefaultConfigName
+	dir := ""
+
+	if p.Config != nil {
+		name = p.Config.Name
+		dir = p.Config.Dir
+	}
+
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+
+		dir = filepath.Join(base, p.Name)
+	}
+
+	return filepath.Join(dir, name+".json")
+}
+
+// new_config_command creates the built-in config command of the given store.
+//
+// Parameters:
+//   - store: The store. Assumed to not be nil.
+//
+// Returns:
+//   - *Command: The config command. Never returns nil.
+func new_config_command(store *Store) *Command {
+	key := StringArg("key")
+	key.Env = EnvNone
+	key.CompleteFn = func(_ *Program, _ string) []string {
+		return slices.Clone(store.order)
+	}
+
+	value := StringArg("value")
+	value.Env = EnvNone
+
+	cmd := &Command{
+		Name:    "config",
+		Brief:   "Reads and writes the persistent settings",
+		builtin: true,
+	}
+
+	cmd.AddCommands(
+		&Command{
+			Name:     "get",
+			Brief:    "Prints the value of a setting",
+			Argument: NewArgument(key),
+			RunFn: func(p *Program, args []string) error {
+				value, err := store.Get(args[0])
+				if err != nil {
+					return err
+				}
+
+				_, err = fmt.Fprintln(p, format_setting(value))
+				return err
+			},
+		},
+		&Command{
+			Name:     "set",
+			Brief:    "Sets the value of a setting",
+			Argument: NewArgument(key, value),
+			RunFn: func(_ *Program, args []string) error {
+				return store.Set(args[0], args[1])
+			},
+		},
+		&Command{
+			Name:     "unset",
+			Brief:    "Restores the default value of a setting",
+			Argument: NewArgument(key),
+			RunFn: func(_ *Program, args []string) error {
+				return store.Unset(args[0])
+			},
+		},
+		&Command{
+			Name:  "list",
+			Brief: "Lists the settings and their values",
+			RunFn: func(p *Program, _ []string) error {
+				return p.print_settings(store)
+			},
+		},
+	)
+
+	return cmd
+}
+
+// print_settings prints the settings of the given store; one line per setting made
+// of its key, its value, whether it is the default and its description.
+//
+// Parameters:
+//   - store: The store. Assumed to not be nil.
+//
+// Returns:
+//   - error: An error if a value could not be read or printed.
+func (p *Program) print_settings(store *Store) error {
+	var rows [][]string
+
+	for _, spec := range store.Specs() {
+		value, err := store.Get(spec.Key)
+		if err != nil {
+			return err
+		}
+
+		is_set, err := store.IsSet(spec.Key)
+		if err != nil {
+			return err
+		}
+
+		var origin string
+		if !is_set {
+			origin = "(default)"
+		}
+
+		rows = append(rows, []string{spec.Key, format_setting(value), origin, spec.Description})
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for _, line := range align_rows(rows, "") {
+		_, err := fmt.Fprintln(p, line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// format_setting formats the value of a setting.
+//
+// Parameters:
+//   - value: The value.
+//
+// Returns:
+//   - string: The formatted value.
+func format_setting(value any) string {
+	str, ok := setting_string(value)
+	if ok {
+		return str
+	}
+
+	return fmt.Sprint(value)
+}
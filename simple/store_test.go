@@ -0,0 +1,334 @@
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// new_test_store creates the store used by the tests of this file, backed by the
+// file "settings.json" of the given directory. If content is not empty, the file is
+// written with it first.
+func new_test_store(t *testing.T, dir, content string) *Store {
+	t.Helper()
+
+	path := filepath.Join(dir, "settings.json")
+
+	if content != "" {
+		write_test_file(t, dir, "settings.json", content)
+	}
+
+	s := NewStore(
+		&SettingSpec{Key: " greet.count ", Type: SettingInt, Default: 1},
+		&SettingSpec{Key: "greet.name", Type: SettingString, Default: "world"},
+		&SettingSpec{Key: "ratio", Type: SettingFloat},
+		&SettingSpec{Key: "debug", Type: SettingBool},
+		&SettingSpec{
+			Key:  "timeout",
+			Type: SettingDuration,
+			Validate: func(value any) error {
+				if value.(time.Duration) <= 0 {
+					return errors.New("timeout must be positive")
+				}
+
+				return nil
+			},
+		},
+	)
+	s.Path = path
+
+	err := s.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return s
+}
+
+// read_test_file returns the content of the given file, without its surrounding
+// blanks, or fails the test.
+func read_test_file(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return strings.Join(strings.Fields(string(data)), "")
+}
+
+func TestStoreGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		want    any
+		err     any
+	}{
+		{"default", "", "greet.count", 1, nil},
+		{"zero default", "", "ratio", 0.0, nil},
+		{"nested", `{"greet": {"count": 3}}`, "greet.count", 3, nil},
+		{"flat", `{"greet.count": 3}`, "greet.count", 3, nil},
+		{"int from a float", `{"ratio": 2}`, "ratio", 2.0, nil},
+		{"duration from a string", `{"timeout": "1m30s"}`, "timeout", 90 * time.Second, nil},
+		{"fractional int", `{"greet": {"count": 3.5}}`, "greet.count", nil, new(*ErrInvalidSetting)},
+		{"string for a bool", `{"debug": "true"}`, "debug", nil, new(*ErrInvalidSetting)},
+		{"number for a string", `{"greet": {"name": 3}}`, "greet.name", nil, new(*ErrInvalidSetting)},
+		{"unknown key", "", "greet.cont", nil, new(*ErrUnknownSetting)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new_test_store(t, t.TempDir(), tt.content)
+
+			got, err := s.Get(tt.key)
+
+			if tt.err != nil {
+				if !errors.As(err, tt.err) {
+					t.Errorf("error = %v, want a %T", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Get(%q) = %v (%T), want %v (%T)", tt.key, got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		value   string
+		want    string
+		err     any
+	}{
+		{"new file", "", "greet.count", "3", `{"greet":{"count":3}}`, nil},
+		{"nested", `{"greet": {"count": 3, "name": "moon"}}`, "greet.count", "5", `{"greet":{"count":5,"name":"moon"}}`, nil},
+		{"flat key is replaced", `{"greet.count": 3}`, "greet.count", "5", `{"greet":{"count":5}}`, nil},
+		{"unknown entries are kept", `{"other": [1, 2]}`, "debug", "true", `{"debug":true,"other":[1,2]}`, nil},
+		{"duration", "", "timeout", "1m30s", `{"timeout":"1m30s"}`, nil},
+		{"unknown key", "", "greet.cont", "3", "", new(*ErrUnknownSetting)},
+		{"invalid value", "", "greet.count", "three", "", new(*ErrInvalidSetting)},
+		{"rejected value", "", "timeout", "-1s", "", new(*ErrInvalidSetting)},
+		{"parent is not an object", `{"greet": "hi"}`, "greet.count", "3", `{"greet": "hi"}`, new(*ErrInvalidSetting)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new_test_store(t, t.TempDir(), tt.content)
+
+			err := s.Set(tt.key, tt.value)
+
+			if tt.err != nil {
+				if !errors.As(err, tt.err) {
+					t.Errorf("error = %v, want a %T", err, tt.err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.want == "" {
+				_, err := os.Stat(s.Path)
+				if !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("the file was written: %v", err)
+				}
+			} else if got := read_test_file(t, s.Path); got != strings.Join(strings.Fields(tt.want), "") {
+				t.Errorf("file = %s, want %s", got, tt.want)
+			}
+
+			if tt.err != nil {
+				return
+			}
+
+			value, err := s.Get(tt.key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if format_setting(value) != tt.value {
+				t.Errorf("Get(%q) = %v after setting %q", tt.key, value, tt.value)
+			}
+		})
+	}
+}
+
+func TestStoreUnset(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		want    string
+	}{
+		{"nested", `{"greet": {"count": 3, "name": "moon"}}`, "greet.count", `{"greet":{"name":"moon"}}`},
+		{"empty objects are removed", `{"greet": {"count": 3}, "debug": true}`, "greet.count", `{"debug":true}`},
+		{"flat and nested", `{"greet.count": 2, "greet": {"count": 3}}`, "greet.count", `{}`},
+		{"not set", `{"debug": true}`, "greet.count", `{"debug": true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new_test_store(t, t.TempDir(), tt.content)
+
+			err := s.Unset(tt.key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := read_test_file(t, s.Path)
+			if got != strings.Join(strings.Fields(tt.want), "") {
+				t.Errorf("file = %s, want %s", got, tt.want)
+			}
+
+			is_set, err := s.IsSet(tt.key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if is_set {
+				t.Errorf("%q is still set", tt.key)
+			}
+		})
+	}
+
+	s := new_test_store(t, t.TempDir(), "")
+
+	err := s.Unset("greet.cont")
+
+	var unknown *ErrUnknownSetting
+
+	if !errors.As(err, &unknown) {
+		t.Errorf("error = %v, want an *ErrUnknownSetting", err)
+	}
+}
+
+func TestStoreSave(t *testing.T) {
+	dir := t.TempDir()
+
+	s := new_test_store(t, dir, `{"debug": true}`)
+
+	for _, value := range []string{"1", "2", "3"} {
+		err := s.Set("greet.count", value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The temporary files are renamed over the file; none is left behind.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Name() != "settings.json" {
+		t.Errorf("unexpected files %v", entries)
+	}
+
+	got := read_test_file(t, s.Path)
+	if got != `{"debug":true,"greet":{"count":3}}` {
+		t.Errorf("file = %s", got)
+	}
+
+	// A failed write leaves the store as it was.
+	s.Path = ""
+
+	err = s.Set("greet.count", "4")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	is_set, err := s.IsSet("greet.count")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if is_set {
+		t.Error("a value that was not written is set")
+	}
+}
+
+func TestStoreIsSetBrokenFile(t *testing.T) {
+	s := new_test_store(t, t.TempDir(), `{"greet": `)
+
+	_, err := s.IsSet("greet.count")
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRunConfigStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []*SettingSpec
+		content string
+		args    []string
+		want    string
+	}{
+		{
+			name:    "get nested",
+			specs:   []*SettingSpec{{Key: "greet.count", Type: SettingInt}},
+			content: `{"greet": {"count": 3}}`,
+			args:    []string{"config", "get", "greet.count"},
+			want:    "3\n",
+		},
+		{
+			name:    "list",
+			specs:   []*SettingSpec{{Key: "greet.count", Type: SettingInt, Description: "Greetings"}, {Key: "debug", Type: SettingBool}},
+			content: `{"greet": {"count": 3}}`,
+			args:    []string{"config", "list"},
+			want:    "greet.count  3                 Greetings\ndebug        false  (default)\n",
+		},
+		{
+			name: "list empty",
+			args: []string{"config", "list"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			dir := t.TempDir()
+
+			p := &Program{
+				Name:  "tool",
+				Out:   &out,
+				Err:   &out,
+				Store: NewStore(tt.specs...),
+			}
+
+			p.Store.Path = filepath.Join(dir, "settings.json")
+
+			if tt.content != "" {
+				write_test_file(t, dir, "settings.json", tt.content)
+			}
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run(append([]string{"tool"}, tt.args...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package simple
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultSuggestionDistance is the default maximum edit distance between an
+	// unknown command and the commands suggested in its place.
+	DefaultSuggestionDistance int = 2
+)
+
+// suggestion is a candidate command name along with its distance to the unknown
+// command.
+type suggestion struct {
+	// name is the name of the candidate.
+	name string
+
+	// distance is the edit distance between the candidate and the unknown command.
+	distance int
+}
+
+// Suggest returns the names of the given candidates that are similar to the given
+// name; that is, the ones that start with it or whose edit distance to it is at
+// most max_distance. The comparison is case-insensitive.
+//
+// Parameters:
+//   - name: The unknown name.
+//   - candidates: The known names.
+//   - max_distance: The maximum edit distance. If negative, nothing is suggested.
+//
+// Returns:
+//   - []string: The suggestions, closest first.
+func Suggest(name string, candidates []string, max_distance int) []string {
+	if max_distance < 0 || name == "" {
+		return nil
+	}
+
+	lower := strings.ToLower(name)
+
+	var suggestions []suggestion
+
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+
+		c := strings.ToLower(candidate)
+
+		distance := levenshtein(lower, c)
+
+		if distance <= max_distance || strings.HasPrefix(c, lower) {
+			suggestions = append(suggestions, suggestion{
+				name:     candidate,
+				distance: distance,
+			})
+		}
+	}
+
+	slices.SortFunc(suggestions, func(a, b suggestion) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+
+		return strings.Compare(a.name, b.name)
+	})
+
+	names := make([]string, 0, len(suggestions))
+
+	for _, s := range suggestions {
+		names = append(names, s.name)
+	}
+
+	return slices.Compact(names)
+}
+
+// levenshtein computes the edit distance between the two given strings.
+//
+// Parameters:
+//   - a: The first string.
+//   - b: The second string.
+//
+// Returns:
+//   - int: The minimum number of insertions, deletions and substitutions of runes
+//     needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
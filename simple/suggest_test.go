@@ -0,0 +1,134 @@
+package simple
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"status", "status", 0},
+		{"stats", "status", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+
+	for _, tt := range tests {
+		got := levenshtein(tt.a, tt.b)
+		if got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"status", "stash", "remote", "remove", "Commit", "rm"}
+
+	tests := []struct {
+		name     string
+		input    string
+		distance int
+		want     []string
+	}{
+		{"closest first", "stats", 2, []string{"status", "stash"}},
+		{"threshold", "stats", 1, []string{"status"}},
+		{"zero distance", "stats", 0, nil},
+		{"opted out", "stats", -1, nil},
+		{"case folding", "COMMTI", 2, []string{"Commit"}},
+		{"prefix beyond the threshold", "rem", 0, []string{"remote", "remove"}},
+		{"exact match is skipped", "rm", 0, nil},
+		{"empty name", "", 2, nil},
+		{"nothing similar", "deploy", 2, nil},
+	}
+
+	for _, tt := range tests {
+		got := Suggest(tt.input, candidates, tt.distance)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	got := Suggest("stat", []string{"status", "status"}, 2)
+	if !slices.Equal(got, []string{"status"}) {
+		t.Errorf("Suggest() with duplicates = %q, want [status]", got)
+	}
+}
+
+func TestRunSuggestions(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance int
+		args     []string
+		want     []string
+	}{
+		{"top-level", 0, []string{"tool", "stats"}, []string{"status", "stash"}},
+		{"alias", 0, []string{"tool", "rx"}, []string{"rm"}},
+		{"sub-command", 0, []string{"tool", "remote", "ad"}, []string{"add"}},
+		{"hidden commands are not suggested", 0, []string{"tool", "secrt"}, nil},
+		{"opted out", -1, []string{"tool", "stats"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, err_out bytes.Buffer
+
+			remote := &Command{Name: "remote", Aliases: []string{"rm"}}
+			remote.AddCommands(&Command{Name: "add"})
+
+			p := &Program{
+				Name:               "tool",
+				Out:                &out,
+				Err:                &err_out,
+				SuggestionDistance: tt.distance,
+				ExitPolicy:         &ExitPolicy{Pause: PauseNever, ExitFn: func(int) {}},
+			}
+
+			p.AddCommands(
+				&Command{Name: "status"},
+				&Command{Name: "stash"},
+				remote,
+				&Command{Name: "secret", Hidden: true},
+			)
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run(tt.args)
+
+			var unknown *ErrUnknownCommand
+
+			if !errors.As(err, &unknown) {
+				t.Fatalf("error = %v, want an *ErrUnknownCommand", err)
+			}
+
+			if !slices.Equal(unknown.Suggestions, tt.want) {
+				t.Errorf("suggestions = %q, want %q", unknown.Suggestions, tt.want)
+			}
+
+			p.ExitSequence(err)
+
+			report := err_out.String()
+
+			if strings.Contains(report, "Did you mean this?") != (len(tt.want) > 0) {
+				t.Errorf("unexpected report %q", report)
+			}
+
+			for _, suggestion := range tt.want {
+				if !strings.Contains(report, "\t"+suggestion+"\n") {
+					t.Errorf("report %q does not suggest %q", report, suggestion)
+				}
+			}
+		})
+	}
+}
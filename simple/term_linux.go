@@ -0,0 +1,11 @@
+package simple
+
+import "golang.org/x/sys/unix"
+
+const (
+	// ioctl_get_termios is the request that reads the mode of a terminal.
+	ioctl_get_termios uint = unix.TCGETS
+
+	// ioctl_set_termios is the request that changes the mode of a terminal.
+	ioctl_set_termios uint = unix.TCSETS
+)
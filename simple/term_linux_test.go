@@ -0,0 +1,251 @@
+package simple
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// open_pty opens a pseudo-terminal, or skips the test if none is available.
+//
+// Returns:
+//   - *os.File: The controller side; what is written to it is read from the
+//     terminal.
+//   - *os.File: The terminal.
+func open_pty(t *testing.T) (*os.File, *os.File) {
+	t.Helper()
+
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal available: %v", err)
+	}
+
+	t.Cleanup(func() { ptmx.Close() })
+
+	err = unix.IoctlSetPointerInt(int(ptmx.Fd()), unix.TIOCSPTLCK, 0)
+	if err != nil {
+		t.Skipf("cannot unlock the pseudo-terminal: %v", err)
+	}
+
+	n, err := unix.IoctlGetInt(int(ptmx.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		t.Skipf("cannot find the pseudo-terminal: %v", err)
+	}
+
+	tty, err := os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("cannot open the pseudo-terminal: %v", err)
+	}
+
+	t.Cleanup(func() { tty.Close() })
+
+	return ptmx, tty
+}
+
+// open_dev_null opens /dev/null, or fails the test.
+func open_dev_null(t *testing.T) *os.File {
+	t.Helper()
+
+	f, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+func TestIsTerminal(t *testing.T) {
+	_, tty := open_pty(t)
+
+	pipe_reader, pipe_writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer pipe_reader.Close()
+	defer pipe_writer.Close()
+
+	tests := []struct {
+		name   string
+		stream any
+		want   bool
+	}{
+		{"not a file", strings.NewReader(""), false},
+		{"nil file", (*os.File)(nil), false},
+		{"null device", open_dev_null(t), false},
+		{"pipe", pipe_reader, false},
+		{"pseudo-terminal", tty, true},
+	}
+
+	for _, tt := range tests {
+		got := IsTerminal(tt.stream)
+		if got != tt.want {
+			t.Errorf("IsTerminal(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// new_test_prompt_program creates the program used by the tests of the prompts for
+// missing arguments. Its "add" command takes a name and a count.
+func new_test_prompt_program(in, out *os.File, got *[]string) *Program {
+	add := &Command{
+		Name:     "add",
+		Argument: NewArgument(StringArg("name"), IntArg("count")),
+		RunFn: func(_ *Program, args []string) error {
+			*got = args
+			return nil
+		},
+	}
+
+	p := &Program{
+		Name: "tool",
+		In:   in,
+		Out:  out,
+		Err:  out,
+	}
+
+	p.AddCommands(add)
+
+	return p
+}
+
+func TestRunPromptsForMissingArguments(t *testing.T) {
+	ptmx, tty := open_pty(t)
+
+	var got []string
+
+	p := new_test_prompt_program(tty, tty, &got)
+
+	err := p.Fix()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first answer is not a number and must be asked again.
+	_, err = ptmx.WriteString("many\n7\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = p.Run([]string{"tool", "add", "bob"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(got, " ") != "bob 7" {
+		t.Errorf("arguments = %q, want [bob 7]", got)
+	}
+}
+
+func TestRunDoesNotPrompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		in_tty bool
+		opt    func(p *Program)
+	}{
+		{"input is the null device", false, nil},
+		{"program opted out", true, func(p *Program) { p.NoPrompt = true }},
+		{"command opted out", true, func(p *Program) {
+			cmd, _ := p.RetrieveCommand("add")
+			cmd.NoPrompt = true
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tty := open_pty(t)
+
+			in := open_dev_null(t)
+			if tt.in_tty {
+				in = tty
+			}
+
+			var got []string
+
+			p := new_test_prompt_program(in, tty, &got)
+
+			if tt.opt != nil {
+				tt.opt(p)
+			}
+
+			err := p.Fix()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = p.Run([]string{"tool", "add"})
+
+			var few *ErrFewArguments
+
+			if !errors.As(err, &few) {
+				t.Fatalf("error = %v, want an *ErrFewArguments", err)
+			}
+
+			code := p.ExitCode(err)
+			if code != ExitUsage {
+				t.Errorf("exit code = %d, want %d", code, ExitUsage)
+			}
+		})
+	}
+}
+
+func TestShouldPause(t *testing.T) {
+	_, tty := open_pty(t)
+	dev_null := open_dev_null(t)
+
+	no_env := func(string) (string, bool) { return "", false }
+	ci_env := func(key string) (string, bool) { return "true", key == "CI" }
+	no_pause_env := func(key string) (string, bool) { return "1", key == DefaultNoPauseEnv }
+
+	tests := []struct {
+		name string
+		mode PauseMode
+		in   io.Reader
+		env  func(string) (string, bool)
+		want bool
+	}{
+		{"terminal", PauseAuto, tty, no_env, true},
+		{"null device input", PauseAuto, dev_null, no_env, false},
+		{"reader input", PauseAuto, strings.NewReader(""), no_env, false},
+		{"continuous integration", PauseAuto, tty, ci_env, false},
+		{"disabled by the environment", PauseAuto, tty, no_pause_env, false},
+		{"always", PauseAlways, dev_null, no_env, true},
+		{"never", PauseNever, tty, no_env, false},
+	}
+
+	for _, tt := range tests {
+		ep := ExitPolicy{Pause: tt.mode, NoPauseEnv: DefaultNoPauseEnv}
+
+		got := ep.should_pause(tt.in, tty, tt.env)
+		if got != tt.want {
+			t.Errorf("should_pause(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectEscape(t *testing.T) {
+	ptmx, tty := open_pty(t)
+
+	p := Program{Name: "tool", In: tty, Out: tty, Err: tty}
+
+	// A lone ESC cancels the menu rather than waiting for the rest of a sequence.
+	_, err := ptmx.WriteString("j\x1b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = p.Select("Color", []string{"red", "green"}, 0)
+
+	var interrupted *ErrInterrupted
+
+	if !errors.As(err, &interrupted) {
+		t.Errorf("error = %v, want an *ErrInterrupted", err)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || windows)
+
+package simple
+
+import (
+	"errors"
+	"os"
+)
+
+// is_terminal checks whether the given file is a character device, which is the
+// best approximation of a terminal available on this platform.
+//
+// Parameters:
+//   - f: The file.
+//
+// Returns:
+//   - bool: True if the file is a character device, false otherwise.
+func is_terminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// make_raw is not supported on this platform; prompts fall back to line-based
+// input.
+//
+// Parameters:
+//   - f: The terminal.
+//
+// Returns:
+//   - func() error: Always nil.
+//   - error: Always errors.ErrUnsupported.
+func make_raw(f *os.File) (func() error, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,62 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package simple
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// is_terminal checks whether the given file is a terminal; that is, whether its
+// terminal attributes can be read.
+//
+// Parameters:
+//   - f: The file.
+//
+// Returns:
+//   - bool: True if the file is a terminal, false otherwise.
+func is_terminal(f *os.File) bool {
+	_, err := unix.IoctlGetTermios(int(f.Fd()), ioctl_get_termios)
+	return err == nil
+}
+
+// make_raw puts the given terminal into raw mode: input is read one byte at a time,
+// without echo and without the interpretation of control characters such as Ctrl-C.
+// Output processing is kept, so that "\n" still moves to the start of the next line.
+//
+// Parameters:
+//   - f: The terminal.
+//
+// Returns:
+//   - func() error: The function that restores the previous mode. Nil if an error
+//     occurred.
+//   - error: An error if the mode of the terminal could not be read or changed.
+func make_raw(f *os.File) (func() error, error) {
+	fd := int(f.Fd())
+
+	old, err := unix.IoctlGetTermios(fd, ioctl_get_termios)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *old
+
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	err = unix.IoctlSetTermios(fd, ioctl_set_termios, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := func() error {
+		return unix.IoctlSetTermios(fd, ioctl_set_termios, old)
+	}
+
+	return restore, nil
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package simple
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// is_terminal checks whether the given file is a console; that is, whether its
+// console mode can be read. Unlike the mode of the file, this tells the console
+// apart from other character devices such as NUL.
+//
+// Parameters:
+//   - f: The file.
+//
+// Returns:
+//   - bool: True if the file is a console, false otherwise.
+func is_terminal(f *os.File) bool {
+	var mode uint32
+
+	err := windows.GetConsoleMode(windows.Handle(f.Fd()), &mode)
+	return err == nil
+}
+
+// make_raw is not supported on this platform; prompts fall back to line-based
+// input.
+//
+// Parameters:
+//   - f: The terminal.
+//
+// Returns:
+//   - func() error: Always nil.
+//   - error: Always errors.ErrUnsupported.
+func make_raw(f *os.File) (func() error, error) {
+	return nil, errors.ErrUnsupported
+}
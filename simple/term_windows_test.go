@@ -0,0 +1,20 @@
+package simple
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIsTerminalNul(t *testing.T) {
+	f, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer f.Close()
+
+	// NUL is a character device, but not a console.
+	if IsTerminal(f) {
+		t.Error("IsTerminal(NUL) = true, want false")
+	}
+}
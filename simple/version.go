@@ -0,0 +1,146 @@
+package simple
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsed_version is a version of the form "[v]MAJOR[.MINOR[.PATCH...]][-PRERELEASE]
+// [+BUILD]".
+type parsed_version struct {
+	// numbers is the list of the numeric components of the version.
+	numbers []int
+
+	// pre_release is the pre-release of the version. Empty if the version is a
+	// release.
+	pre_release string
+}
+
+// parse_version parses the given version. The build metadata is ignored.
+//
+// Parameters:
+//   - str: The version to parse. (i.e., "v1.2.3-rc.1")
+//
+// Returns:
+//   - parsed_version: The parsed version.
+//   - error: An error if the version is malformed.
+func parse_version(str string) (parsed_version, error) {
+	str = strings.TrimPrefix(strings.TrimSpace(str), "v")
+
+	str, _, _ = strings.Cut(str, "+")
+
+	str, pre_release, _ := strings.Cut(str, "-")
+
+	if str == "" {
+		return parsed_version{}, fmt.Errorf("version cannot be empty")
+	}
+
+	fields := strings.Split(str, ".")
+	numbers := make([]int, 0, len(fields))
+
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parsed_version{}, fmt.Errorf("component %q is not a non-negative integer", field)
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	return parsed_version{
+		numbers:     numbers,
+		pre_release: pre_release,
+	}, nil
+}
+
+// CompareVersions compares two versions of the form "[v]MAJOR[.MINOR[.PATCH]]
+// [-PRERELEASE][+BUILD]". Missing components count as 0 and a pre-release comes
+// before its release. (i.e., "1.2-rc.1" < "1.2.0" == "v1.2")
+//
+// Parameters:
+//   - a: The first version.
+//   - b: The second version.
+//
+// Returns:
+//   - int: -1 if a < b, 0 if a == b and 1 if a > b.
+//   - error: An error if either version is malformed.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parse_version(a)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q: %w", a, err)
+	}
+
+	vb, err := parse_version(b)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q: %w", b, err)
+	}
+
+	for i := 0; i < max(len(va.numbers), len(vb.numbers)); i++ {
+		var na, nb int
+
+		if i < len(va.numbers) {
+			na = va.numbers[i]
+		}
+
+		if i < len(vb.numbers) {
+			nb = vb.numbers[i]
+		}
+
+		res := cmp.Compare(na, nb)
+		if res != 0 {
+			return res, nil
+		}
+	}
+
+	switch {
+	case va.pre_release == vb.pre_release:
+		return 0, nil
+	case va.pre_release == "":
+		return 1, nil
+	case vb.pre_release == "":
+		return -1, nil
+	}
+
+	return compare_pre_releases(va.pre_release, vb.pre_release), nil
+}
+
+// compare_pre_releases compares two pre-releases identifier by identifier, as
+// semantic versioning does; numeric identifiers compare numerically and come
+// before alphanumeric ones.
+//
+// Parameters:
+//   - a: The first pre-release.
+//   - b: The second pre-release.
+//
+// Returns:
+//   - int: -1 if a < b, 0 if a == b and 1 if a > b.
+func compare_pre_releases(a, b string) int {
+	ids_a := strings.Split(a, ".")
+	ids_b := strings.Split(b, ".")
+
+	for i := 0; i < len(ids_a) && i < len(ids_b); i++ {
+		na, err_a := strconv.Atoi(ids_a[i])
+		nb, err_b := strconv.Atoi(ids_b[i])
+
+		var res int
+
+		switch {
+		case err_a == nil && err_b == nil:
+			res = cmp.Compare(na, nb)
+		case err_a == nil:
+			res = -1
+		case err_b == nil:
+			res = 1
+		default:
+			res = strings.Compare(ids_a[i], ids_b[i])
+		}
+
+		if res != 0 {
+			return res
+		}
+	}
+
+	return cmp.Compare(len(ids_a), len(ids_b))
+}